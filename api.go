@@ -23,8 +23,11 @@ type Empty struct{}
 // Set provides a collection of operations for sets
 //
 // The implementation of Set is base on hash table. So the elements must be
-// hashable, functions, maps, slices are unhashable type, adding these elements
-// will cause panic.
+// hashable, functions, maps, slices are unhashable type, and so are structs,
+// arrays and interfaces holding them. Adding these elements returns an
+// *UnhashableError which wraps ErrUnhashable, and the constructors panic
+// with it.
+// Use NewKeyedSet to hold unhashable elements by their derived keys.
 //
 // There are two implementations of Set:
 // 1. default is unsafe based on hash table(map)
@@ -32,13 +35,20 @@ type Empty struct{}
 //
 // The two kinds of sets can easily convert to the other one. But you must know
 // exactly what you are doing to avoid the concurrent race
+//
+// Both implementations can be marshaled to and unmarshaled from JSON. Sets of
+// ints or strings are encoded as plain arrays, the others are encoded in a
+// type-tagged form so that element types are kept after decoding.
 type Set interface {
 	SetToSlice
 	// Add adds all given elements to the set anyway, no matter if it whether already exists.
+	// It is all-or-nothing, if any of elements is invalid, the set is
+	// left unchanged and the error is returned.
 	Add(elem ...interface{}) error
 
 	// Extend adds all elements in the given interface b to this set
 	// the given interface must be array, slice or Set.
+	// Like Add, the set is left unchanged if it returns an error.
 	Extend(b interface{}) error
 
 	// Remove deletes all given elements from the set.
@@ -47,6 +57,10 @@ type Set interface {
 	// Contains checks whether the given elem is in the set.
 	Contains(elem interface{}) bool
 
+	// ContainsErr is like Contains, but it returns an *UnhashableError
+	// instead of false if the given elem is unhashable.
+	ContainsErr(elem interface{}) (bool, error)
+
 	// ContainsAll checks whether all the given elems are in the set.
 	ContainsAll(elems ...interface{}) bool
 
@@ -119,6 +133,181 @@ type Set interface {
 	// Intersect returns the intersection of two set, aka Intersection Set
 	// math formula: a ∩ b
 	Intersect(b Set) Set
+
+	// ---------------------------------------------------------------------
+	// In-place Set Operations
+	//
+	// The in-place operations modify this set instead of returning a new
+	// one. Thread safe sets hold the write lock of this set and the read
+	// lock of the given set.
+
+	// UniteWith adds all elements in the given set to this set.
+	// If this set can not hold some of the elements, the set is left
+	// unchanged and the error is returned.
+	// math formula: a = a ∪ b
+	UniteWith(b Set) error
+
+	// IntersectWith removes the elements not in the given set from this set.
+	// math formula: a = a ∩ b
+	IntersectWith(b Set)
+
+	// DiffWith removes the elements in the given set from this set.
+	// math formula: a = a - b
+	DiffWith(b Set)
+
+	// SymmetricDiffWith removes the elements in the given set from this
+	// set, and adds the elements only in the given set. Like UniteWith,
+	// the set is left unchanged if an error is returned.
+	// math formula: a = (a - b) ∪ (b - a)
+	SymmetricDiffWith(b Set) error
+}
+
+// OrderedSet is a Set which remembers the order in which elements are
+// first inserted.
+//
+// Range, Elements, ToInts, ToStrings and String visit elements in that
+// order. Copy, Diff, SymmetricDiff, Unite and Intersect return ordered sets
+// which keep the order of this set, and elements only in the given set are
+// appended in the order of the given set. Unite and SymmetricDiff return
+// unordered sets if the given set contains elements which are not hashable.
+type OrderedSet interface {
+	Set
+
+	// First returns the earliest inserted element in the set.
+	// If the set is empty, it returns false.
+	First() (interface{}, bool)
+
+	// Last returns the latest inserted element in the set.
+	// If the set is empty, it returns false.
+	Last() (interface{}, bool)
+
+	// IndexOf returns the position of the given elem in the insertion
+	// order, or -1 if the elem is not in the set. It takes O(n) time.
+	IndexOf(elem interface{}) int
+
+	// MoveToEnd moves the given elem to the end of the insertion order as
+	// if it is inserted just now. It returns false if the elem is not in
+	// the set.
+	MoveToEnd(elem interface{}) bool
+}
+
+// SortedSet is a Set which keeps its elements in ascending order by a
+// Comparator.
+//
+// Without a user given Comparator, only int and string elements can be
+// added, ints are ordered before strings. Elements which are equal by the
+// Comparator are considered as the same element.
+//
+// Range, Elements, ToInts, ToStrings and String visit elements in ascending
+// order. Diff, SymmetricDiff, Unite and Intersect return sorted sets with
+// the same Comparator, except that Unite and SymmetricDiff return unordered
+// sets if the given set contains elements which can not be ordered by the
+// default Comparator.
+type SortedSet interface {
+	Set
+
+	// Min returns the smallest element in the set.
+	// If the set is empty, it returns false.
+	Min() (interface{}, bool)
+
+	// Max returns the greatest element in the set.
+	// If the set is empty, it returns false.
+	Max() (interface{}, bool)
+
+	// Floor returns the greatest element in the set less than or equal
+	// to the given elem. If there is no such element, it returns false.
+	Floor(elem interface{}) (interface{}, bool)
+
+	// Ceiling returns the smallest element in the set greater than or
+	// equal to the given elem. If there is no such element, it returns
+	// false.
+	Ceiling(elem interface{}) (interface{}, bool)
+
+	// RangeBetween returns all elements between lo and hi inclusively
+	// in ascending order.
+	RangeBetween(lo, hi interface{}) []interface{}
+
+	// Rank returns the number of elements in the set which are less than
+	// the given elem. In other words, it is the index of the elem if the
+	// elem is in the set. It returns -1 if the elem can not be ordered.
+	Rank(elem interface{}) int
+
+	// Select returns the i-th smallest element in the set, counting from
+	// zero. If i is out of range, it returns false.
+	Select(i int) (interface{}, bool)
+}
+
+// Bag is a multiset, which remembers how many times each element is added.
+// Like Set, int and string elements are stored in their own typed buckets,
+// the elements must be hashable.
+//
+// Bag is not thread safe.
+type Bag interface {
+	// Add adds n occurrences of the given elem to the bag. It returns an
+	// error if n is negative or elem is unhashable.
+	Add(elem interface{}, n int) error
+
+	// Remove removes at most n occurrences of the given elem from the bag.
+	// The elem is removed from the bag if its count drops to zero.
+	Remove(elem interface{}, n int)
+
+	// RemoveAll removes all occurrences of the given elem from the bag.
+	RemoveAll(elem interface{})
+
+	// Count returns how many times the given elem occurs in the bag.
+	Count(elem interface{}) int
+
+	// Len returns the total number of occurrences of all elements.
+	Len() int
+
+	// DistinctLen returns the number of distinct elements in the bag.
+	DistinctLen() int
+
+	// Distinct returns a new Set containing all distinct elements.
+	Distinct() Set
+
+	// Range calls f sequentially for each distinct element present in the
+	// bag with its count. If f returns false, range stops the iteration.
+	Range(foreach func(elem interface{}, count int) bool)
+
+	// Elements returns all elements in the bag, an element occurs in the
+	// slice as many times as its count.
+	Elements() []interface{}
+
+	// Copy clones the bag.
+	Copy() Bag
+
+	// String returns the string representation of the bag.
+	String() string
+
+	// Equal checks whether the two bags contain the same elements with
+	// the same counts.
+	Equal(b Bag) bool
+
+	// IsSubsetOf checks whether the count of every element in this bag is
+	// not greater than its count in the given bag.
+	IsSubsetOf(b Bag) bool
+
+	// Unite returns the union of two bags, the count of an element is the
+	// greater count of it in the two bags.
+	// math formula: max(a(x), b(x))
+	Unite(b Bag) Bag
+
+	// Sum returns the sum of two bags, the count of an element is the
+	// sum of its counts in the two bags.
+	// math formula: a(x) + b(x)
+	Sum(b Bag) Bag
+
+	// Intersect returns the intersection of two bags, the count of an
+	// element is the smaller count of it in the two bags.
+	// math formula: min(a(x), b(x))
+	Intersect(b Bag) Bag
+
+	// Diff returns the difference of two bags, the count of an element is
+	// its count in this bag minus its count in the given bag, elements
+	// with non-positive results are dropped.
+	// math formula: max(a(x) - b(x), 0)
+	Diff(b Bag) Bag
 }
 
 // SetToSlice contains methods that knows how to convert set to slice.
@@ -202,3 +391,271 @@ func NewSafeSetFromStrings(e []string) Set {
 func NewSafeSetFromFloats(e []float64) Set {
 	return NewSafeSetFrom(e)
 }
+
+// NewOrderedSet returns a new OrderedSet which contains
+// the given elements in order
+func NewOrderedSet(elems ...interface{}) OrderedSet {
+	return newOrderedSet(elems...)
+}
+
+// NewOrderedSetFrom returns a new OrderedSet from the given collection.
+// the collection must be array, slice or Set,
+// otherwise it will panic
+func NewOrderedSetFrom(i interface{}) OrderedSet {
+	s := newOrderedSet()
+	err := s.Extend(i)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// NewSafeOrderedSet returns a new thread-safe OrderedSet
+// which contains the given elements in order
+func NewSafeOrderedSet(elems ...interface{}) OrderedSet {
+	return newThreadSafeOrderedSet(elems...)
+}
+
+// NewSortedSet returns a new SortedSet of ints and strings
+// which contains the given elements
+func NewSortedSet(elems ...interface{}) SortedSet {
+	return newSortedSet(nil, elems...)
+}
+
+// NewSortedSetWithComparator returns a new SortedSet ordered by
+// the given comparator which contains the given elements
+func NewSortedSetWithComparator(compare Comparator, elems ...interface{}) SortedSet {
+	return newSortedSet(compare, elems...)
+}
+
+// NewSafeSortedSet returns a new thread-safe SortedSet of ints
+// and strings which contains the given elements
+func NewSafeSortedSet(elems ...interface{}) SortedSet {
+	return newThreadSafeSortedSet(nil, elems...)
+}
+
+// NewSafeSortedSetWithComparator returns a new thread-safe SortedSet
+// ordered by the given comparator which contains the given elements
+func NewSafeSortedSetWithComparator(compare Comparator, elems ...interface{}) SortedSet {
+	return newThreadSafeSortedSet(compare, elems...)
+}
+
+// NewBitmapSet returns a new Set of ints in [0, 1<<32-1] backed by a
+// bitmap, which contains the given elements, otherwise it will panic. It
+// takes one bit per int up to the greatest element, so it is much smaller
+// than NewSetFromInts for dense ints. Adding any element which is not an
+// int in the range returns an error.
+//
+// Operations between two bitmap sets are done word by word. Unite and
+// SymmetricDiff with a set containing other elements return a hash table
+// based set.
+//
+// Use NewSetFrom to convert a bitmap set to a hash table based one, and
+// NewBitmapSetFrom to convert it back.
+func NewBitmapSet(elems ...int) Set {
+	return newBitmapSet(elems...)
+}
+
+// NewBitmapSetFrom returns a new bitmap Set from the given collection.
+// the collection must be array, slice or Set of ints in [0, 1<<32-1],
+// otherwise it will panic
+func NewBitmapSetFrom(i interface{}) Set {
+	s := newBitmapSet()
+	err := s.Extend(i)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// NewSafeBitmapSet returns a new thread-safe bitmap Set
+// which contains the given elements
+func NewSafeBitmapSet(elems ...int) Set {
+	return newBitmapSet(elems...).ToThreadSafe()
+}
+
+// NewShardedSet returns a new thread-safe Set which contains the given
+// elements. Elements are partitioned by their hashes into the given number
+// of shards, and each shard has its own lock, so that Add, Remove and
+// Contains on different shards do not block each other. If shards is not
+// positive, a default number is used.
+//
+// Len, Equal, Range and the other operations on the whole set hold the read
+// locks of all shards, so they see a consistent snapshot of the set. The
+// binary set operations return sharded sets with the same number of shards.
+func NewShardedSet(shards int, elems ...interface{}) Set {
+	return newShardedSet(shards, elems...)
+}
+
+// NewImmutableSet returns a new persistent ImmutableSet which contains the
+// given elements. Every modification returns a new version sharing
+// structure with the old one, so it can be passed between goroutines as a
+// snapshot without copying or locking.
+func NewImmutableSet(elems ...interface{}) *ImmutableSet {
+	return (&ImmutableSet{}).With(elems...)
+}
+
+// NewImmutableSetFrom returns a new ImmutableSet which contains all
+// elements in the given Set.
+func NewImmutableSetFrom(s Set) *ImmutableSet {
+	return newImmutableSetFrom(s)
+}
+
+// NewBag returns a new Bag which contains the given elements, an element
+// given multiple times is counted multiple times.
+func NewBag(elems ...interface{}) Bag {
+	return newBag(elems...)
+}
+
+// NewBagFrom returns a new Bag from the given array or slice,
+// otherwise it will panic
+func NewBagFrom(i interface{}) Bag {
+	b := newBag()
+	if err := extendBag(b, i); err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// NewKeyedSet returns a new Set which buckets elements by the keys derived
+// from the given KeyFunc instead of the elements themselves, so that
+// unhashable elements like structs containing slices can be deduplicated.
+// If key is nil, elements implementing Keyer are keyed by SetKey, and the
+// others by themselves. The keys must be hashable, otherwise adding the
+// elements returns an *UnhashableError.
+//
+// Elements, Range and the other methods return the original elements, if
+// multiple elements have the same key, the first added one is kept.
+// Operations with another set compare the elements of the given set by the
+// KeyFunc of this set, and return keyed sets with the same KeyFunc.
+func NewKeyedSet(key KeyFunc, elems ...interface{}) Set {
+	return newKeyedSet(key, elems...)
+}
+
+// NewSafeKeyedSet returns a new thread-safe keyed Set
+// which contains the given elements
+func NewSafeKeyedSet(key KeyFunc, elems ...interface{}) Set {
+	return newKeyedSet(key, elems...).ToThreadSafe()
+}
+
+// NewBloomFilter returns a new BloomFilter sized to hold the expected
+// number of elements with the given false positive rate, which must be in
+// (0, 1), otherwise it will panic. Adding more elements than expected
+// increases the false positive rate.
+func NewBloomFilter(expected int, falsePositiveRate float64) *BloomFilter {
+	return newBloomFilter(expected, falsePositiveRate)
+}
+
+// NewBloomFilterFrom returns a new BloomFilter containing all elements in
+// the given Set, sized by the cardinality of the set.
+func NewBloomFilterFrom(s Set, falsePositiveRate float64) *BloomFilter {
+	f := newBloomFilter(s.Len(), falsePositiveRate)
+	s.Range(func(_ int, elem interface{}) bool {
+		f.Add(elem)
+		return true
+	})
+	return f
+}
+
+// NewHyperLogLog returns a new HyperLogLog sketch with 2^precision
+// registers, precision must be in [4, 18], otherwise it will panic.
+// A higher precision gives more accurate estimates with more memory.
+func NewHyperLogLog(precision int) *HyperLogLog {
+	return newHyperLogLog(precision)
+}
+
+// NewHyperLogLogFrom returns a new HyperLogLog sketch with all elements in
+// the given Set added.
+func NewHyperLogLogFrom(s Set, precision int) *HyperLogLog {
+	h := newHyperLogLog(precision)
+	h.AddSet(s)
+	return h
+}
+
+// NewCuckooFilter returns a new CuckooFilter which can hold about the given
+// capacity of elements. fingerprintBits must be in [4, 16], otherwise it
+// will panic, longer fingerprints give lower false positive rates.
+func NewCuckooFilter(capacity, fingerprintBits int) *CuckooFilter {
+	return newCuckooFilter(capacity, fingerprintBits)
+}
+
+// NewCuckooFilterFrom returns a new CuckooFilter containing all elements in
+// the given Set, sized by the cardinality of the set. It returns
+// ErrFilterFull in the rare case that some elements can not be placed.
+func NewCuckooFilterFrom(s Set, fingerprintBits int) (*CuckooFilter, error) {
+	f := newCuckooFilter(s.Len(), fingerprintBits)
+	if err := f.AddSet(s); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// NewCountMinSketch returns a new CountMinSketch whose estimates exceed the
+// true counts by at most epsilon times the total count with probability
+// 1-delta. epsilon and delta must be in (0, 1), otherwise it will panic.
+func NewCountMinSketch(epsilon, delta float64) *CountMinSketch {
+	return newCountMinSketch(epsilon, delta)
+}
+
+// NewTopK returns a new TopK tracking the k most frequent elements with a
+// CountMinSketch of the given epsilon and delta.
+func NewTopK(k int, epsilon, delta float64) *TopK {
+	return newTopK(k, epsilon, delta)
+}
+
+// NewMinHash returns the MinHash signature of the given Set with size hash
+// functions. A larger size gives more accurate estimates.
+func NewMinHash(s Set, size int) MinHash {
+	return newMinHash(s, size)
+}
+
+// NewFingerprint returns the Fingerprint of the given Set, see Fingerprint
+// for how elements are encoded.
+func NewFingerprint(s Set) Fingerprint {
+	return newFingerprint(s)
+}
+
+// NewLSHIndex returns a new LSHIndex of MinHash signatures of the given
+// size, which finds sets whose Jaccard index with the query is not less
+// than threshold. threshold must be in (0, 1], otherwise it will panic.
+func NewLSHIndex(size int, threshold float64) *LSHIndex {
+	return newLSHIndex(size, threshold)
+}
+
+// ParseExpr parses a set expression like `A & (B | C) - D`, see Expr for
+// the syntax. It returns an *ExprError with the position of the first
+// syntax error.
+func ParseExpr(expr string) (*Expr, error) {
+	return parseExpr(expr)
+}
+
+// EvalExpr parses the set expression and evaluates it with the named sets.
+func EvalExpr(expr string, sets map[string]Set) (Set, error) {
+	e, err := parseExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	return e.Eval(sets)
+}
+
+// NewRegistry returns a new empty Registry of named sets.
+func NewRegistry() *Registry {
+	return newRegistry()
+}
+
+// NewGSet returns a new grow-only GSet CRDT with the given elements. It
+// panics if any element is unhashable.
+func NewGSet(elems ...interface{}) *GSet {
+	return newGSet(elems...)
+}
+
+// NewTwoPhaseSet returns a new empty two-phase TwoPhaseSet CRDT.
+func NewTwoPhaseSet() *TwoPhaseSet {
+	return newTwoPhaseSet()
+}
+
+// NewORSet returns a new empty observed-remove ORSet CRDT of the given
+// replica, which must be unique among the replicas of the set.
+func NewORSet(replica string) *ORSet {
+	return newORSet(replica)
+}
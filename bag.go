@@ -0,0 +1,258 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+// bag is a multiset. The counts are bucketed by typedAssert like the typed
+// sets in typedSetGroup, so that ints and strings are kept in maps of their
+// own types, and 1, "1" and int64(1) are different elements.
+type bag struct {
+	ints   map[int]int
+	strs   map[string]int
+	others map[interface{}]int
+	total  int
+}
+
+func newBag(elems ...interface{}) *bag {
+	b := &bag{
+		ints:   make(map[int]int),
+		strs:   make(map[string]int),
+		others: make(map[interface{}]int),
+	}
+	for _, elem := range elems {
+		if err := b.Add(elem, 1); err != nil {
+			panic(err)
+		}
+	}
+	return b
+}
+
+// get returns the count of the hashable elem.
+func (s *bag) get(elem interface{}) int {
+	switch typedAssert(elem) {
+	case typedInt:
+		return s.ints[elem.(int)]
+	case typedString:
+		return s.strs[elem.(string)]
+	}
+	return s.others[elem]
+}
+
+// set sets the count of the hashable elem, the element is deleted if n is
+// zero.
+func (s *bag) set(elem interface{}, n int) {
+	s.total += n - s.get(elem)
+	switch typedAssert(elem) {
+	case typedInt:
+		if n == 0 {
+			delete(s.ints, elem.(int))
+		} else {
+			s.ints[elem.(int)] = n
+		}
+	case typedString:
+		if n == 0 {
+			delete(s.strs, elem.(string))
+		} else {
+			s.strs[elem.(string)] = n
+		}
+	default:
+		if n == 0 {
+			delete(s.others, elem)
+		} else {
+			s.others[elem] = n
+		}
+	}
+}
+
+func (s *bag) Add(elem interface{}, n int) error {
+	if n < 0 {
+		return fmt.Errorf("error add %v to bag with negative count %d", elem, n)
+	}
+	if !hashable(elem) {
+		return unhashableError(elem, 0)
+	}
+	if n > 0 {
+		s.set(elem, s.get(elem)+n)
+	}
+	return nil
+}
+
+func (s *bag) Remove(elem interface{}, n int) {
+	if n <= 0 || !hashable(elem) {
+		return
+	}
+	s.set(elem, maxInt(s.get(elem)-n, 0))
+}
+
+func (s *bag) RemoveAll(elem interface{}) {
+	if hashable(elem) {
+		s.set(elem, 0)
+	}
+}
+
+func (s *bag) Count(elem interface{}) int {
+	if !hashable(elem) {
+		return 0
+	}
+	return s.get(elem)
+}
+
+func (s *bag) Len() int {
+	return s.total
+}
+
+func (s *bag) DistinctLen() int {
+	return len(s.ints) + len(s.strs) + len(s.others)
+}
+
+func (s *bag) Distinct() Set {
+	ret := newSet()
+	s.Range(func(elem interface{}, _ int) bool {
+		// elements of s are always hashable
+		ret.typedSetFor(elem).Add(elem)
+		return true
+	})
+	return ret
+}
+
+func (s *bag) Range(foreach func(elem interface{}, count int) bool) {
+	for elem, n := range s.ints {
+		if !foreach(elem, n) {
+			return
+		}
+	}
+	for elem, n := range s.strs {
+		if !foreach(elem, n) {
+			return
+		}
+	}
+	for elem, n := range s.others {
+		if !foreach(elem, n) {
+			return
+		}
+	}
+}
+
+func (s *bag) Elements() []interface{} {
+	ret := make([]interface{}, 0, s.total)
+	s.Range(func(elem interface{}, n int) bool {
+		for i := 0; i < n; i++ {
+			ret = append(ret, elem)
+		}
+		return true
+	})
+	return ret
+}
+
+func (s *bag) Copy() Bag {
+	ret := newBag()
+	s.Range(func(elem interface{}, n int) bool {
+		ret.set(elem, n)
+		return true
+	})
+	return ret
+}
+
+func (s *bag) String() string {
+	buf := bytes.Buffer{}
+	buf.WriteString("Bag[")
+	first := true
+	s.Range(func(elem interface{}, n int) bool {
+		if !first {
+			buf.WriteString(" ")
+		}
+		first = false
+		buf.WriteString(fmt.Sprintf("%+v:%d", elem, n))
+		return true
+	})
+	buf.WriteString("]")
+	return buf.String()
+}
+
+func (s *bag) Equal(b Bag) bool {
+	return s.Len() == b.Len() && s.DistinctLen() == b.DistinctLen() && s.IsSubsetOf(b)
+}
+
+func (s *bag) IsSubsetOf(b Bag) bool {
+	if s.Len() > b.Len() {
+		return false
+	}
+	ret := true
+	s.Range(func(elem interface{}, n int) bool {
+		ret = n <= b.Count(elem)
+		return ret
+	})
+	return ret
+}
+
+// combine returns a new bag in which the count of every element is f(x, y),
+// x and y are the counts of the element in s and b.
+func (s *bag) combine(b Bag, f func(x, y int) int) Bag {
+	ret := newBag()
+	s.Range(func(elem interface{}, n int) bool {
+		ret.set(elem, f(n, b.Count(elem)))
+		return true
+	})
+	b.Range(func(elem interface{}, n int) bool {
+		if s.get(elem) == 0 {
+			ret.set(elem, f(0, n))
+		}
+		return true
+	})
+	return ret
+}
+
+func (s *bag) Unite(b Bag) Bag {
+	return s.combine(b, maxInt)
+}
+
+func (s *bag) Sum(b Bag) Bag {
+	return s.combine(b, func(x, y int) int { return x + y })
+}
+
+func (s *bag) Intersect(b Bag) Bag {
+	return s.combine(b, minInt)
+}
+
+func (s *bag) Diff(b Bag) Bag {
+	return s.combine(b, func(x, y int) int { return maxInt(x-y, 0) })
+}
+
+// extendBag adds all elements in the array or slice i to the bag s.
+func extendBag(s *bag, i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Array && v.Kind() != reflect.Slice {
+		return fmt.Errorf("error extend bag with kind: %v, only support array and slice", v.Kind())
+	}
+	for j := 0; j < v.Len(); j++ {
+		if err := s.Add(v.Index(j).Interface(), 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"testing"
+)
+
+func Test_bag_Add_And_Remove(t *testing.T) {
+	s := NewBag(1, 1, "a", 2.5)
+	if err := s.Add("a", 3); err != nil {
+		t.Fatalf("bag.Add() error = %v", err)
+	}
+	if err := s.Add(1, -1); err == nil {
+		t.Errorf("bag.Add() negative count should return error")
+	}
+	if err := s.Add([]int{1}, 1); err == nil {
+		t.Errorf("bag.Add() unhashable element should return error")
+	}
+	s.Remove(1, 1)
+	s.Remove(2.5, 10)
+	s.Remove("missing", 1)
+
+	tests := []struct {
+		elem interface{}
+		want int
+	}{
+		{1, 1},
+		{"a", 4},
+		{2.5, 0},
+		{"missing", 0},
+		{[]int{1}, 0},
+	}
+	for _, tt := range tests {
+		if got := s.Count(tt.elem); got != tt.want {
+			t.Errorf("bag.Count(%v) = %v, want %v", tt.elem, got, tt.want)
+		}
+	}
+	if s.Len() != 5 || s.DistinctLen() != 2 {
+		t.Errorf("bag.Len() = %v, DistinctLen() = %v, want 5, 2", s.Len(), s.DistinctLen())
+	}
+	if got := s.Distinct(); !got.Equal(NewSet(1, "a")) {
+		t.Errorf("bag.Distinct() = %v", got)
+	}
+	if got := len(s.Elements()); got != 5 {
+		t.Errorf("len(bag.Elements()) = %v, want 5", got)
+	}
+	s.RemoveAll("a")
+	if got := s.String(); got != "Bag[1:1]" {
+		t.Errorf("bag.String() = %v", got)
+	}
+
+	// elements are bucketed like set, so 1, "1" and int64(1) are different
+	mixed := NewBag(1, "1", int64(1), int64(1))
+	if got := mixed.Distinct(); mixed.DistinctLen() != 3 || !got.Equal(NewSet(1, "1", int64(1))) {
+		t.Errorf("bag.Distinct() = %v, want 3 elements", got)
+	}
+	if mixed.Count(int64(1)) != 2 || mixed.Count(1) != 1 {
+		t.Errorf("bag.Count() mixes elements of different types")
+	}
+}
+
+func Test_bag_Operations(t *testing.T) {
+	a := NewBagFrom([]interface{}{1, 1, 1, "x", "y", "y", 3.0})
+	b := NewBagFrom([]interface{}{1, "y", "y", "y", "z", 3.0})
+
+	tests := []struct {
+		name string
+		got  Bag
+		want Bag
+	}{
+		{"Unite", a.Unite(b), NewBag(1, 1, 1, "x", "y", "y", "y", "z", 3.0)},
+		{"Sum", a.Sum(b), NewBag(1, 1, 1, 1, "x", "y", "y", "y", "y", "y", "z", 3.0, 3.0)},
+		{"Intersect", a.Intersect(b), NewBag(1, "y", "y", 3.0)},
+		{"Diff", a.Diff(b), NewBag(1, 1, "x")},
+		{"Copy", a.Copy(), a},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.got.Equal(tt.want) {
+				t.Errorf("bag.%s() = %v, want %v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+	if a.Equal(b) {
+		t.Errorf("bag.Equal() = true, want false")
+	}
+	if !a.Intersect(b).IsSubsetOf(a) || a.IsSubsetOf(a.Intersect(b)) {
+		t.Errorf("bag.IsSubsetOf() gives wrong result")
+	}
+}
+
+func Test_NewBagFrom_Panic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewBagFrom() with non slice should panic")
+		}
+	}()
+	NewBagFrom(1)
+}
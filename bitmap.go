@@ -0,0 +1,490 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+)
+
+// maxBitmapElem is the greatest element of bitmap sets, so that a single
+// element can grow the words to 512 MiB at most.
+const maxBitmapElem = 1<<32 - 1
+
+// bitmapSet is a set of ints in [0, maxBitmapElem], the element i is in the
+// set if the i-th bit of words is set.
+type bitmapSet struct {
+	words []uint64
+	count int
+}
+
+func newBitmapSet(elems ...int) *bitmapSet {
+	s := &bitmapSet{}
+	for _, elem := range elems {
+		if _, ok := bitmapIndex(elem); !ok {
+			panic(bitmapError(elem))
+		}
+		s.add(elem)
+	}
+	return s
+}
+
+func bitmapError(elem interface{}) error {
+	return fmt.Errorf("error add %v of type %T to bitmap set, only int in [0, %d] is supported", elem, elem, maxBitmapElem)
+}
+
+// bitmapIndex returns elem as a bit index if it is an int in
+// [0, maxBitmapElem].
+func bitmapIndex(elem interface{}) (int, bool) {
+	i, ok := elem.(int)
+	return i, ok && i >= 0 && i <= maxBitmapElem
+}
+
+func (s *bitmapSet) add(i int) {
+	w := i / 64
+	if w >= len(s.words) {
+		s.grow(w + 1)
+	}
+	mask := uint64(1) << uint(i%64)
+	if s.words[w]&mask == 0 {
+		s.words[w] |= mask
+		s.count++
+	}
+}
+
+// grow extends the words to length n, using the spare capacity if it is
+// enough and doubling the capacity otherwise, so that sequential adds take
+// amortized constant time.
+func (s *bitmapSet) grow(n int) {
+	if n <= cap(s.words) {
+		old := len(s.words)
+		s.words = s.words[:n]
+		// the spare capacity may hold words of a trimmed set
+		for j := old; j < n; j++ {
+			s.words[j] = 0
+		}
+		return
+	}
+	words := make([]uint64, n, maxInt(n, 2*cap(s.words)))
+	copy(words, s.words)
+	s.words = words
+}
+
+func (s *bitmapSet) contains(i int) bool {
+	w := i / 64
+	return w < len(s.words) && s.words[w]&(uint64(1)<<uint(i%64)) != 0
+}
+
+// fromWords returns a new bitmap set with the given words.
+func fromWords(words []uint64) *bitmapSet {
+	// trim the trailing empty words
+	for len(words) > 0 && words[len(words)-1] == 0 {
+		words = words[:len(words)-1]
+	}
+	s := &bitmapSet{words: words}
+	for _, w := range words {
+		s.count += bits.OnesCount64(w)
+	}
+	return s
+}
+
+func (s *bitmapSet) Add(elems ...interface{}) error {
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		if _, ok := bitmapIndex(elem); !ok {
+			return bitmapError(elem)
+		}
+	}
+	for _, elem := range elems {
+		s.add(elem.(int))
+	}
+	return nil
+}
+
+func (s *bitmapSet) Extend(b interface{}) error {
+	return extend(s, b)
+}
+
+func (s *bitmapSet) Remove(elems ...interface{}) {
+	for _, elem := range elems {
+		i, ok := bitmapIndex(elem)
+		if !ok || !s.contains(i) {
+			continue
+		}
+		s.words[i/64] &^= uint64(1) << uint(i%64)
+		s.count--
+	}
+}
+
+func (s *bitmapSet) Contains(elem interface{}) bool {
+	i, ok := bitmapIndex(elem)
+	return ok && s.contains(i)
+}
+
+func (s *bitmapSet) ContainsErr(elem interface{}) (bool, error) {
+	if !hashable(elem) {
+		return false, unhashableError(elem, 0)
+	}
+	return s.Contains(elem), nil
+}
+
+func (s *bitmapSet) ContainsAll(elems ...interface{}) bool {
+	for _, elem := range elems {
+		if !s.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *bitmapSet) ContainsAny(elems ...interface{}) bool {
+	for _, elem := range elems {
+		if s.Contains(elem) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *bitmapSet) Copy() Set {
+	words := make([]uint64, len(s.words))
+	copy(words, s.words)
+	return &bitmapSet{words: words, count: s.count}
+}
+
+func (s *bitmapSet) Len() int {
+	return s.count
+}
+
+func (s *bitmapSet) String() string {
+	return formatSet(s)
+}
+
+func (s *bitmapSet) Range(foreach func(index int, elem interface{}) bool) {
+	i := 0
+	for w, word := range s.words {
+		for word != 0 {
+			if !foreach(i, w*64+bits.TrailingZeros64(word)) {
+				return
+			}
+			i++
+			// clear the lowest set bit
+			word &= word - 1
+		}
+	}
+}
+
+func (s *bitmapSet) Elements() []interface{} {
+	ret := make([]interface{}, 0, s.count)
+	s.Range(func(_ int, elem interface{}) bool {
+		ret = append(ret, elem)
+		return true
+	})
+	return ret
+}
+
+func (s *bitmapSet) ToStrings() []string {
+	return []string{}
+}
+
+func (s *bitmapSet) ToInts() []int {
+	ret := make([]int, 0, s.count)
+	s.Range(func(_ int, elem interface{}) bool {
+		ret = append(ret, elem.(int))
+		return true
+	})
+	return ret
+}
+
+func (s *bitmapSet) ToThreadUnsafe() Set {
+	return s
+}
+
+func (s *bitmapSet) ToThreadSafe() Set {
+	return &threadSafeSet{unsafe: s}
+}
+
+func (s *bitmapSet) Equal(b Set) bool {
+	b = b.ToThreadUnsafe()
+	return s.count == b.Len() && s.IsSubsetOf(b)
+}
+
+func (s *bitmapSet) IsSubsetOf(b Set) bool {
+	b = b.ToThreadUnsafe()
+	if s.count > b.Len() {
+		return false
+	}
+	if s2, ok := b.(*bitmapSet); ok {
+		for w, word := range s.words {
+			if word&^s2.word(w) != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	ret := true
+	s.Range(func(_ int, elem interface{}) bool {
+		ret = b.Contains(elem)
+		return ret
+	})
+	return ret
+}
+
+func (s *bitmapSet) IsSupersetOf(b Set) bool {
+	b = b.ToThreadUnsafe()
+	if s2, ok := b.(*bitmapSet); ok {
+		return s2.IsSubsetOf(s)
+	}
+	if b.Len() > s.count {
+		return false
+	}
+	ret := true
+	b.Range(func(_ int, elem interface{}) bool {
+		ret = s.Contains(elem)
+		return ret
+	})
+	return ret
+}
+
+// word returns the w-th word, or zero if w is out of range.
+func (s *bitmapSet) word(w int) uint64 {
+	if w < len(s.words) {
+		return s.words[w]
+	}
+	return 0
+}
+
+// combine applies op to the words of s and b word by word.
+func (s *bitmapSet) combine(b *bitmapSet, n int, op func(x, y uint64) uint64) *bitmapSet {
+	words := make([]uint64, n)
+	for w := range words {
+		words[w] = op(s.word(w), b.word(w))
+	}
+	return fromWords(words)
+}
+
+// filter returns a new bitmap set containing the elements in s for which
+// keep returns true.
+func (s *bitmapSet) filter(keep func(elem interface{}) bool) *bitmapSet {
+	ret := &bitmapSet{}
+	s.Range(func(_ int, elem interface{}) bool {
+		if keep(elem) {
+			ret.add(elem.(int))
+		}
+		return true
+	})
+	return ret
+}
+
+// toBitmap returns b as a bitmap set, it returns false if b contains
+// any element which is not an int in [0, maxBitmapElem].
+func toBitmap(b Set) (*bitmapSet, bool) {
+	if s, ok := b.(*bitmapSet); ok {
+		return s, true
+	}
+	ret := &bitmapSet{}
+	ok := true
+	b.Range(func(_ int, elem interface{}) bool {
+		var i int
+		i, ok = bitmapIndex(elem)
+		if ok {
+			ret.add(i)
+		}
+		return ok
+	})
+	return ret, ok
+}
+
+func (s *bitmapSet) Diff(b Set) Set {
+	b = b.ToThreadUnsafe()
+	if s2, ok := b.(*bitmapSet); ok {
+		return s.combine(s2, len(s.words), func(x, y uint64) uint64 {
+			return x &^ y
+		})
+	}
+	return s.filter(func(elem interface{}) bool {
+		return !b.Contains(elem)
+	})
+}
+
+func (s *bitmapSet) SymmetricDiff(b Set) Set {
+	b = b.ToThreadUnsafe()
+	s2, ok := toBitmap(b)
+	if !ok {
+		return newSet(s.Elements()...).SymmetricDiff(b)
+	}
+	return s.combine(s2, maxInt(len(s.words), len(s2.words)), func(x, y uint64) uint64 {
+		return x ^ y
+	})
+}
+
+func (s *bitmapSet) Unite(b Set) Set {
+	b = b.ToThreadUnsafe()
+	s2, ok := toBitmap(b)
+	if !ok {
+		return newSet(s.Elements()...).Unite(b)
+	}
+	return s.combine(s2, maxInt(len(s.words), len(s2.words)), func(x, y uint64) uint64 {
+		return x | y
+	})
+}
+
+func (s *bitmapSet) Intersect(b Set) Set {
+	b = b.ToThreadUnsafe()
+	if s2, ok := b.(*bitmapSet); ok {
+		return s.combine(s2, minInt(len(s.words), len(s2.words)), func(x, y uint64) uint64 {
+			return x & y
+		})
+	}
+	return s.filter(b.Contains)
+}
+
+// combineWith applies op to the words of s and b word by word in place,
+// s is grown to the size of b if grow is true.
+func (s *bitmapSet) combineWith(b *bitmapSet, grow bool, op func(x, y uint64) uint64) {
+	if n := len(b.words); grow && n > len(s.words) {
+		words := make([]uint64, n)
+		copy(words, s.words)
+		s.words = words
+	}
+	s.count = 0
+	for w := range s.words {
+		s.words[w] = op(s.words[w], b.word(w))
+		s.count += bits.OnesCount64(s.words[w])
+	}
+	s.trim()
+}
+
+// trim removes the trailing empty words.
+func (s *bitmapSet) trim() {
+	for len(s.words) > 0 && s.words[len(s.words)-1] == 0 {
+		s.words = s.words[:len(s.words)-1]
+	}
+}
+
+// toBitmapErr is like toBitmap, but returns the error of the first element
+// which is not an int in [0, maxBitmapElem].
+func toBitmapErr(b Set) (*bitmapSet, error) {
+	if s, ok := toBitmap(b); ok {
+		return s, nil
+	}
+	var err error
+	b.Range(func(_ int, elem interface{}) bool {
+		if _, ok := bitmapIndex(elem); !ok {
+			err = bitmapError(elem)
+		}
+		return err == nil
+	})
+	return nil, err
+}
+
+func (s *bitmapSet) UniteWith(b Set) error {
+	s2, err := toBitmapErr(b.ToThreadUnsafe())
+	if err != nil {
+		return err
+	}
+	s.combineWith(s2, true, func(x, y uint64) uint64 {
+		return x | y
+	})
+	return nil
+}
+
+func (s *bitmapSet) IntersectWith(b Set) {
+	b = b.ToThreadUnsafe()
+	if s2, ok := b.(*bitmapSet); ok {
+		s.combineWith(s2, false, func(x, y uint64) uint64 {
+			return x & y
+		})
+		return
+	}
+	for w, word := range s.words {
+		for x := word; x != 0; x &= x - 1 {
+			i := w*64 + bits.TrailingZeros64(x)
+			if !b.Contains(i) {
+				s.words[w] &^= uint64(1) << uint(i%64)
+				s.count--
+			}
+		}
+	}
+	s.trim()
+}
+
+func (s *bitmapSet) DiffWith(b Set) {
+	b = b.ToThreadUnsafe()
+	if s2, ok := b.(*bitmapSet); ok {
+		s.combineWith(s2, false, func(x, y uint64) uint64 {
+			return x &^ y
+		})
+		return
+	}
+	b.Range(func(_ int, elem interface{}) bool {
+		s.Remove(elem)
+		return true
+	})
+}
+
+func (s *bitmapSet) SymmetricDiffWith(b Set) error {
+	s2, err := toBitmapErr(b.ToThreadUnsafe())
+	if err != nil {
+		return err
+	}
+	s.combineWith(s2, true, func(x, y uint64) uint64 {
+		return x ^ y
+	})
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, the set is encoded as a plain
+// JSON array in ascending order.
+func (s *bitmapSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToInts())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces all elements in
+// the set with the decoded ones.
+func (s *bitmapSet) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalElements(data)
+	if err != nil {
+		return err
+	}
+	if elems == nil {
+		return nil
+	}
+	ret := &bitmapSet{}
+	if err := ret.Add(elems...); err != nil {
+		return err
+	}
+	*s = *ret
+	return nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
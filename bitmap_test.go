@@ -0,0 +1,236 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func Test_bitmapSet_Add(t *testing.T) {
+	tests := []struct {
+		name    string
+		elems   []interface{}
+		want    []int
+		wantErr bool
+	}{
+		{"", []interface{}{130, 1, 64, 1}, []int{1, 64, 130}, false},
+		{"negative", []interface{}{1, -1}, []int{}, true},
+		{"string", []interface{}{"1"}, []int{}, true},
+		{"too large", []interface{}{1, 1 << 40}, []int{}, true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewBitmapSet()
+			if err := s.Add(tt.elems...); (err != nil) != tt.wantErr {
+				t.Errorf("bitmapSet.Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := s.ToInts(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bitmapSet.ToInts() = %v, want %v", got, tt.want)
+			}
+			if s.Len() != len(tt.want) {
+				t.Errorf("bitmapSet.Len() = %v, want %v", s.Len(), len(tt.want))
+			}
+		})
+	}
+}
+
+func Test_bitmapIndex(t *testing.T) {
+	if _, ok := bitmapIndex(maxBitmapElem); !ok {
+		t.Errorf("bitmapIndex(maxBitmapElem) = false")
+	}
+	// a single element must not allocate terabytes
+	if _, ok := bitmapIndex(maxBitmapElem + 1); ok {
+		t.Errorf("bitmapIndex(maxBitmapElem + 1) = true")
+	}
+}
+
+func Test_bitmapSet_Add_Grow(t *testing.T) {
+	// sequential adds reuse the spare capacity
+	allocs := testing.AllocsPerRun(1, func() {
+		s := newBitmapSet()
+		for i := 0; i < 1<<16; i++ {
+			s.add(i)
+		}
+	})
+	if allocs > 20 {
+		t.Errorf("adding 1<<16 sequential ints allocates %v times", allocs)
+	}
+
+	// stale words of a trimmed set are cleared when growing again
+	s := NewBitmapSet(1, 200)
+	s.Remove(200)
+	s.Add(300) //nolint:errcheck
+	if got := s.ToInts(); !reflect.DeepEqual(got, []int{1, 300}) {
+		t.Errorf("bitmapSet.ToInts() = %v, want [1 300]", got)
+	}
+}
+
+func Test_bitmapSet_Remove_And_Contains(t *testing.T) {
+	s := NewBitmapSet(1, 2, 100)
+	s.Remove(2, 1000, -1, "x")
+	tests := []struct {
+		elem interface{}
+		want bool
+	}{
+		{1, true},
+		{2, false},
+		{100, true},
+		{1000, false},
+		{-1, false},
+		{"1", false},
+	}
+	for _, tt := range tests {
+		if got := s.Contains(tt.elem); got != tt.want {
+			t.Errorf("bitmapSet.Contains(%v) = %v, want %v", tt.elem, got, tt.want)
+		}
+	}
+	if s.Len() != 2 {
+		t.Errorf("bitmapSet.Len() = %v, want 2", s.Len())
+	}
+}
+
+func Test_bitmapSet_Operations(t *testing.T) {
+	tests := []struct {
+		name      string
+		a         Set
+		b         Set
+		diff      Set
+		symmetric Set
+		union     Set
+		intersect Set
+	}{
+		{
+			"bitmap",
+			NewBitmapSet(1, 2, 64, 200),
+			NewBitmapSet(2, 64, 65),
+			NewSet(1, 200),
+			NewSet(1, 200, 65),
+			NewSet(1, 2, 64, 65, 200),
+			NewSet(2, 64),
+		},
+		{
+			"ints",
+			NewBitmapSet(1, 2, 3),
+			NewSafeSet(3, 4),
+			NewSet(1, 2),
+			NewSet(1, 2, 4),
+			NewSet(1, 2, 3, 4),
+			NewSet(3),
+		},
+		{
+			"mixed",
+			NewBitmapSet(1, 2, 3),
+			NewSet(3, "4", -1),
+			NewSet(1, 2),
+			NewSet(1, 2, "4", -1),
+			NewSet(1, 2, 3, "4", -1),
+			NewSet(3),
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Diff(tt.b); !got.Equal(tt.diff) {
+				t.Errorf("bitmapSet.Diff() = %v, want %v", got, tt.diff)
+			}
+			if got := tt.a.SymmetricDiff(tt.b); !got.Equal(tt.symmetric) {
+				t.Errorf("bitmapSet.SymmetricDiff() = %v, want %v", got, tt.symmetric)
+			}
+			if got := tt.a.Unite(tt.b); !got.Equal(tt.union) {
+				t.Errorf("bitmapSet.Unite() = %v, want %v", got, tt.union)
+			}
+			if got := tt.a.Intersect(tt.b); !got.Equal(tt.intersect) {
+				t.Errorf("bitmapSet.Intersect() = %v, want %v", got, tt.intersect)
+			}
+		})
+	}
+}
+
+func Test_bitmapSet_Compare(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        Set
+		b        Set
+		equal    bool
+		subset   bool
+		superset bool
+	}{
+		{"", NewBitmapSet(1, 100), NewBitmapSet(100, 1), true, true, true},
+		{"", NewBitmapSet(1), NewBitmapSet(1, 100), false, true, false},
+		{"", NewBitmapSet(1, 100), NewSet(1), false, false, true},
+		{"", NewSet(1, 100), NewBitmapSet(1, 100), true, true, true},
+		{"", NewBitmapSet(1), NewSet(1, "1"), false, true, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.equal {
+				t.Errorf("Equal() = %v, want %v", got, tt.equal)
+			}
+			if got := tt.a.IsSubsetOf(tt.b); got != tt.subset {
+				t.Errorf("IsSubsetOf() = %v, want %v", got, tt.subset)
+			}
+			if got := tt.a.IsSupersetOf(tt.b); got != tt.superset {
+				t.Errorf("IsSupersetOf() = %v, want %v", got, tt.superset)
+			}
+		})
+	}
+}
+
+func Test_bitmapSet_Convert(t *testing.T) {
+	s := NewSetFromInts([]int{3, 1, 2})
+	bitmap := NewBitmapSetFrom(s)
+	if got := bitmap.ToInts(); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("NewBitmapSetFrom() = %v, want [1 2 3]", got)
+	}
+	if got := NewSetFrom(bitmap); !got.Equal(s) {
+		t.Errorf("NewSetFrom() = %v, want %v", got, s)
+	}
+	if _, ok := NewSafeBitmapSet(1).ToThreadUnsafe().(*bitmapSet); !ok {
+		t.Errorf("NewSafeBitmapSet().ToThreadUnsafe() is not a bitmap set")
+	}
+
+	c := bitmap.Copy()
+	c.Add(100)
+	if bitmap.Contains(100) {
+		t.Errorf("bitmapSet.Copy() shares storage with the origin set")
+	}
+}
+
+func Test_bitmapSet_JSON(t *testing.T) {
+	s := NewSafeBitmapSet(64, 1)
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != "[1,64]" {
+		t.Errorf("json.Marshal() = %s, want [1,64]", data)
+	}
+	got := NewBitmapSet()
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !got.Equal(s) {
+		t.Errorf("json.Unmarshal() = %v, want %v", got, s)
+	}
+	if err := json.Unmarshal([]byte(`["1"]`), got); err == nil {
+		t.Errorf("json.Unmarshal() with strings returns no error")
+	}
+}
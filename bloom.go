@@ -0,0 +1,222 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+const bloomVersion byte = 1
+
+// limits of decoded filters, so that a hostile payload can neither make
+// huge allocations nor make every lookup hash too many times.
+const (
+	maxBloomBits   = 1 << 40
+	maxBloomHashes = 1 << 10
+)
+
+// BloomFilter is a space-efficient probabilistic set of elements. It may
+// report that an element is in the filter while it is not (false positive),
+// but never reports that an added element is not in the filter.
+//
+// Elements are hashed like Set bucketing them, ints and strings are hashed
+// stably across processes, so a serialized filter of them can be shipped to
+// other services. Other elements are hashed by their types and values,
+// pointers and channels by their addresses.
+//
+// BloomFilter is not thread safe.
+type BloomFilter struct {
+	words []uint64
+	m     uint64
+	k     uint64
+}
+
+// bloomParams returns the number of bits and hash functions of a filter
+// holding n elements with false positive rate p.
+func bloomParams(n int, p float64) (m, k uint64) {
+	if n < 1 {
+		n = 1
+	}
+	mf := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	m = uint64(mf)
+	if m < 64 {
+		m = 64
+	}
+	k = uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	if k > maxBloomHashes {
+		k = maxBloomHashes
+	}
+	return m, k
+}
+
+func newBloomFilter(n int, p float64) *BloomFilter {
+	if p <= 0 || p >= 1 {
+		panic(fmt.Sprintf("error create bloom filter with false positive rate %v, it must be in (0, 1)", p))
+	}
+	m, k := bloomParams(n, p)
+	return &BloomFilter{
+		words: make([]uint64, (m+63)/64),
+		m:     m,
+		k:     k,
+	}
+}
+
+// Add adds all given elements to the filter.
+func (f *BloomFilter) Add(elems ...interface{}) {
+	for _, elem := range elems {
+		a, b := hashElemPair(elem)
+		for i := uint64(0); i < f.k; i++ {
+			j := (a + i*b) % f.m
+			f.words[j/64] |= 1 << (j % 64)
+		}
+	}
+}
+
+// MayContain reports whether the given elem may be in the filter. It
+// returns false only if elem has never been added.
+func (f *BloomFilter) MayContain(elem interface{}) bool {
+	a, b := hashElemPair(elem)
+	for i := uint64(0); i < f.k; i++ {
+		j := (a + i*b) % f.m
+		if f.words[j/64]&(1<<(j%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Bits returns the number of bits in the filter.
+func (f *BloomFilter) Bits() int {
+	return int(f.m)
+}
+
+// Hashes returns the number of hash functions used for each element.
+func (f *BloomFilter) Hashes() int {
+	return int(f.k)
+}
+
+func (f *BloomFilter) ones() uint64 {
+	var n int
+	for _, w := range f.words {
+		n += bits.OnesCount64(w)
+	}
+	return uint64(n)
+}
+
+// EstimatedLen estimates the number of distinct elements added to the
+// filter from the number of set bits.
+func (f *BloomFilter) EstimatedLen() int {
+	x := f.ones()
+	if x == f.m {
+		return math.MaxInt32
+	}
+	m, k := float64(f.m), float64(f.k)
+	return int(math.Round(-m / k * math.Log(1-float64(x)/m)))
+}
+
+// FalsePositiveRate estimates the current false positive rate of the
+// filter from the number of set bits.
+func (f *BloomFilter) FalsePositiveRate() float64 {
+	return math.Pow(float64(f.ones())/float64(f.m), float64(f.k))
+}
+
+// Copy clones the filter.
+func (f *BloomFilter) Copy() *BloomFilter {
+	words := make([]uint64, len(f.words))
+	copy(words, f.words)
+	return &BloomFilter{words: words, m: f.m, k: f.k}
+}
+
+func (f *BloomFilter) compatible(b *BloomFilter) error {
+	if f.m != b.m || f.k != b.k {
+		return fmt.Errorf("error combine bloom filters with different parameters: %d bits %d hashes and %d bits %d hashes", f.m, f.k, b.m, b.k)
+	}
+	return nil
+}
+
+// Unite returns a new filter which may contain elements in either filter.
+// It is exactly the filter built from the union of the added elements.
+// The two filters must have the same parameters.
+func (f *BloomFilter) Unite(b *BloomFilter) (*BloomFilter, error) {
+	if err := f.compatible(b); err != nil {
+		return nil, err
+	}
+	ret := f.Copy()
+	for i, w := range b.words {
+		ret.words[i] |= w
+	}
+	return ret, nil
+}
+
+// Intersect returns a new filter which may contain elements in both
+// filters. Its false positive rate is not greater than that of the given
+// filters, but may be greater than that of the filter built from the
+// intersection of the added elements, since bits set by elements in only
+// one of the filters may be kept. The two filters must have the same
+// parameters.
+func (f *BloomFilter) Intersect(b *BloomFilter) (*BloomFilter, error) {
+	if err := f.compatible(b); err != nil {
+		return nil, err
+	}
+	ret := f.Copy()
+	for i, w := range b.words {
+		ret.words[i] &= w
+	}
+	return ret, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 17+8*len(f.words))
+	data[0] = bloomVersion
+	binary.BigEndian.PutUint64(data[1:], f.m)
+	binary.BigEndian.PutUint64(data[9:], f.k)
+	for i, w := range f.words {
+		binary.BigEndian.PutUint64(data[17+8*i:], w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 17 || data[0] != bloomVersion {
+		return errors.New("error decode bloom filter: invalid header")
+	}
+	m := binary.BigEndian.Uint64(data[1:])
+	k := binary.BigEndian.Uint64(data[9:])
+	data = data[17:]
+	if m == 0 || m > maxBloomBits || k == 0 || k > maxBloomHashes {
+		return errors.New("error decode bloom filter: invalid parameters")
+	}
+	// (m-1)/64+1 is the number of words, it never overflows
+	if len(data)%8 != 0 || uint64(len(data)/8) != (m-1)/64+1 {
+		return errors.New("error decode bloom filter: invalid size")
+	}
+	words := make([]uint64, len(data)/8)
+	for i := range words {
+		words[i] = binary.BigEndian.Uint64(data[8*i:])
+	}
+	*f = BloomFilter{words: words, m: m, k: k}
+	return nil
+}
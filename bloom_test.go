@@ -0,0 +1,151 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func Test_BloomFilter_Params(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		p        float64
+		wantBits int
+		wantK    int
+	}{
+		{"1000 at 1%", 1000, 0.01, 9586, 7},
+		{"1000 at 0.1%", 1000, 0.001, 14378, 10},
+		{"tiny", 0, 0.5, 64, 44},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewBloomFilter(tt.n, tt.p)
+			if f.Bits() != tt.wantBits || f.Hashes() != tt.wantK {
+				t.Errorf("NewBloomFilter() = %d bits %d hashes, want %d bits %d hashes", f.Bits(), f.Hashes(), tt.wantBits, tt.wantK)
+			}
+		})
+	}
+}
+
+func Test_BloomFilter_MayContain(t *testing.T) {
+	const n = 5000
+	s := NewSet()
+	for i := 0; i < n; i++ {
+		s.Add(i, fmt.Sprintf("url-%d", i)) //nolint:errcheck
+	}
+	f := NewBloomFilterFrom(s, 0.01)
+	s.Range(func(_ int, elem interface{}) bool {
+		if !f.MayContain(elem) {
+			t.Fatalf("BloomFilter.MayContain(%v) = false for added element", elem)
+		}
+		return true
+	})
+
+	fp := 0
+	const probes = 20000
+	for i := 0; i < probes; i++ {
+		if f.MayContain(fmt.Sprintf("other-%d", i)) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / probes; rate > 0.02 {
+		t.Errorf("false positive rate = %v, want about 0.01", rate)
+	}
+	if got := f.EstimatedLen(); got < 2*n*95/100 || got > 2*n*105/100 {
+		t.Errorf("BloomFilter.EstimatedLen() = %v, want about %v", got, 2*n)
+	}
+	if got := f.FalsePositiveRate(); got > 0.02 {
+		t.Errorf("BloomFilter.FalsePositiveRate() = %v", got)
+	}
+}
+
+func Test_BloomFilter_Unite_And_Intersect(t *testing.T) {
+	a := NewBloomFilter(100, 0.01)
+	b := NewBloomFilter(100, 0.01)
+	a.Add(1, 2, "x")
+	b.Add(2, 3, "y")
+
+	u, err := a.Unite(b)
+	if err != nil {
+		t.Fatalf("BloomFilter.Unite() error = %v", err)
+	}
+	for _, elem := range []interface{}{1, 2, 3, "x", "y"} {
+		if !u.MayContain(elem) {
+			t.Errorf("united filter MayContain(%v) = false", elem)
+		}
+	}
+	in, err := a.Intersect(b)
+	if err != nil {
+		t.Fatalf("BloomFilter.Intersect() error = %v", err)
+	}
+	if !in.MayContain(2) || in.MayContain(1) || in.MayContain("y") {
+		t.Errorf("intersected filter gives wrong result")
+	}
+	if a.MayContain(3) {
+		t.Errorf("BloomFilter.Unite() changes the receiver")
+	}
+
+	if _, err := a.Unite(NewBloomFilter(1000, 0.01)); err == nil {
+		t.Errorf("BloomFilter.Unite() incompatible filters should return error")
+	}
+}
+
+func Test_BloomFilter_Binary(t *testing.T) {
+	f := NewBloomFilterFrom(NewSet(1, "a", 2.5), 0.001)
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("BloomFilter.MarshalBinary() error = %v", err)
+	}
+	got := &BloomFilter{}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("BloomFilter.UnmarshalBinary() error = %v", err)
+	}
+	if got.Bits() != f.Bits() || got.Hashes() != f.Hashes() || !got.MayContain("a") || !got.MayContain(2.5) {
+		t.Errorf("BloomFilter.UnmarshalBinary() = %+v, want %+v", got, f)
+	}
+
+	header := func(m, k uint64, words int) []byte {
+		data := make([]byte, 17+8*words)
+		data[0] = bloomVersion
+		binary.BigEndian.PutUint64(data[1:], m)
+		binary.BigEndian.PutUint64(data[9:], k)
+		return data
+	}
+	for _, bad := range [][]byte{
+		nil,
+		data[:10],
+		data[:len(data)-1],
+		append([]byte{9}, data[1:]...),
+		// (m+63)/64 overflows to 0
+		header(math.MaxUint64, 1, 0),
+		header(math.MaxUint64, 1, 1),
+		header(maxBloomBits+64, 1, 0),
+		// too many hashes
+		header(64, 1<<40, 1),
+		header(64, 0, 1),
+		header(0, 1, 0),
+	} {
+		if err := got.UnmarshalBinary(bad); err == nil {
+			t.Errorf("BloomFilter.UnmarshalBinary(%d bytes) should return error", len(bad))
+		}
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command goset performs set operations on newline-delimited files.
+//
+// Usage:
+//
+//	goset [flags] command [file ...]
+//
+// Each file is read into a set of its lines, "-" or no file at all means
+// the standard input. Empty lines are ignored. The commands are:
+//
+//	union     the lines in any file
+//	intersect the lines in all files
+//	diff      the lines in the first file but not in the others
+//	symdiff   the lines in an odd number of files
+//	subset    whether the first file is a subset of the second one
+//	equal     whether the two files have the same lines
+//	count     the number of distinct lines in each file
+//
+// The result lines are printed in the order they first appear in the
+// inputs unless -sort is given. subset and equal print true or false and
+// exit with status 1 if it is false, errors exit with status 2.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/zoumo/goset"
+)
+
+type options struct {
+	trim    bool
+	fold    bool
+	numeric bool
+	sorted  bool
+	count   bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command with the given arguments and returns the exit
+// status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var opts options
+	fs := flag.NewFlagSet("goset", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.BoolVar(&opts.trim, "trim", false, "trim leading and trailing white space of lines")
+	fs.BoolVar(&opts.fold, "fold", false, "compare lines case-insensitively by lowering them")
+	fs.BoolVar(&opts.numeric, "numeric", false, "parse lines as ints, -sort orders them numerically")
+	fs.BoolVar(&opts.sorted, "sort", false, "print the result in ascending order")
+	fs.BoolVar(&opts.count, "c", false, "print the number of result lines instead of the lines")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: goset [flags] union|intersect|diff|symdiff|subset|equal|count [file ...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	cmd, files := fs.Arg(0), fs.Args()[1:]
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	status, err := runCommand(cmd, files, opts, stdin, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "goset: %v\n", err)
+		return 2
+	}
+	return status
+}
+
+func runCommand(cmd string, files []string, opts options, stdin io.Reader, stdout io.Writer) (int, error) {
+	switch cmd {
+	case "union", "intersect", "diff", "symdiff", "count":
+	case "subset", "equal":
+		if len(files) != 2 {
+			return 0, fmt.Errorf("%s requires two files", cmd)
+		}
+	default:
+		return 0, fmt.Errorf("unknown command %q", cmd)
+	}
+
+	sets := make([]goset.Set, len(files))
+	for i, file := range files {
+		s, err := readFile(file, opts, stdin)
+		if err != nil {
+			return 0, err
+		}
+		sets[i] = s
+	}
+
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+
+	switch cmd {
+	case "subset", "equal":
+		ok := sets[0].IsSubsetOf(sets[1])
+		if cmd == "equal" {
+			ok = sets[0].Equal(sets[1])
+		}
+		fmt.Fprintln(w, ok)
+		if !ok {
+			return 1, nil
+		}
+		return 0, nil
+	case "count":
+		for i, s := range sets {
+			fmt.Fprintf(w, "%d\t%s\n", s.Len(), files[i])
+		}
+		return 0, nil
+	}
+
+	// the result is an ordered set, so that the lines are in the order
+	// they first appear
+	ret := sets[0].Copy()
+	for _, s := range sets[1:] {
+		var err error
+		switch cmd {
+		case "union":
+			err = ret.UniteWith(s)
+		case "intersect":
+			ret.IntersectWith(s)
+		case "diff":
+			ret.DiffWith(s)
+		case "symdiff":
+			err = ret.SymmetricDiffWith(s)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if opts.count {
+		fmt.Fprintln(w, ret.Len())
+		return 0, nil
+	}
+	if opts.sorted {
+		ret = goset.NewSortedSet(ret.Elements()...)
+	}
+	ret.Range(func(_ int, elem interface{}) bool {
+		fmt.Fprintln(w, elem)
+		return true
+	})
+	return 0, nil
+}
+
+// readFile reads the lines of the file into an ordered set, "-" means
+// stdin.
+func readFile(file string, opts options, stdin io.Reader) (goset.Set, error) {
+	r := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	s := goset.NewOrderedSet()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if opts.trim {
+			line = strings.TrimSpace(line)
+		}
+		if opts.fold {
+			line = strings.ToLower(line)
+		}
+		if line == "" {
+			continue
+		}
+		var elem interface{} = line
+		if opts.numeric {
+			i, err := strconv.Atoi(strings.TrimSpace(line))
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid int %q", file, n, line)
+			}
+			elem = i
+		}
+		s.Add(elem) //nolint:errcheck
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return s, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_run(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a":   "web-1\nweb-2\n\nDB-1\nweb-1\n",
+		"b":   "  web-2\nweb-3\r\ndb-1\n",
+		"c":   "web-2\n",
+		"ids": "10\n9\n100\n",
+		"bad": "1\ntwo\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantOut    string
+		wantStatus int
+		wantErr    string
+	}{
+		{"union", []string{"union", path("a"), path("b")}, "", "web-1\nweb-2\nDB-1\n  web-2\nweb-3\ndb-1\n", 0, ""},
+		{"union trim fold", []string{"-trim", "-fold", "union", path("a"), path("b")}, "", "web-1\nweb-2\ndb-1\nweb-3\n", 0, ""},
+		{"intersect", []string{"-trim", "intersect", path("a"), path("b"), path("c")}, "", "web-2\n", 0, ""},
+		{"diff", []string{"-fold", "-trim", "diff", path("a"), path("b")}, "", "web-1\n", 0, ""},
+		{"symdiff sorted", []string{"-trim", "-fold", "-sort", "symdiff", path("a"), path("b")}, "", "web-1\nweb-3\n", 0, ""},
+		{"count result", []string{"-c", "union", path("a"), path("c")}, "", "3\n", 0, ""},
+		{"count", []string{"count", path("a"), path("c")}, "", "3\t" + path("a") + "\n1\t" + path("c") + "\n", 0, ""},
+		{"stdin", []string{"diff", "-", path("c")}, "web-2\nweb-4\n", "web-4\n", 0, ""},
+		{"no files", []string{"union"}, "x\ny\nx\n", "x\ny\n", 0, ""},
+		{"numeric sorted", []string{"-numeric", "-sort", "union", path("ids"), "-"}, " 2\n", "2\n9\n10\n100\n", 0, ""},
+		{"subset", []string{"-trim", "subset", path("c"), path("b")}, "", "true\n", 0, ""},
+		{"not subset", []string{"subset", path("a"), path("b")}, "", "false\n", 1, ""},
+		{"equal", []string{"-trim", "-fold", "equal", path("b"), "-"}, "db-1\nWEB-3\nweb-2\n", "true\n", 0, ""},
+		{"equal one file", []string{"equal", path("a")}, "", "", 2, "equal requires two files"},
+		{"invalid int", []string{"-numeric", "union", path("bad")}, "", "", 2, `bad:2: invalid int "two"`},
+		{"missing file", []string{"union", path("missing")}, "", "", 2, "no such file"},
+		{"unknown command", []string{"join", path("a")}, "", "", 2, `unknown command "join"`},
+		{"no command", nil, "", "", 2, "usage"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if status != tt.wantStatus {
+				t.Errorf("run() = %v, want %v, stderr: %s", status, tt.wantStatus, stderr.String())
+			}
+			if got := stdout.String(); got != tt.wantOut {
+				t.Errorf("run() output = %q, want %q", got, tt.wantOut)
+			}
+			if !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("run() stderr = %q, want %q", stderr.String(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// CountMinSketch estimates the frequencies of elements in a stream with
+// memory independent of the number of distinct elements.
+//
+// With the error rate epsilon and the failure probability delta, an
+// estimate is never less than the true count, and exceeds it by at most
+// epsilon*Total() with probability at least 1-delta. The sketch has
+// ceil(e/epsilon) counters in each of ceil(ln(1/delta)) rows.
+//
+// Elements are hashed with their typed buckets as Set does, so 1 and "1"
+// are counted separately. CountMinSketch is not thread safe.
+type CountMinSketch struct {
+	width  uint64
+	depth  uint64
+	counts []uint64
+	total  uint64
+}
+
+func newCountMinSketch(epsilon, delta float64) *CountMinSketch {
+	if epsilon <= 0 || epsilon >= 1 || delta <= 0 || delta >= 1 {
+		panic(fmt.Sprintf("error create count-min sketch with epsilon %v and delta %v, they must be in (0, 1)", epsilon, delta))
+	}
+	width := uint64(math.Ceil(math.E / epsilon))
+	depth := uint64(math.Ceil(math.Log(1 / delta)))
+	return &CountMinSketch{
+		width:  width,
+		depth:  depth,
+		counts: make([]uint64, width*depth),
+	}
+}
+
+// Increment counts one occurrence of the given elem.
+func (c *CountMinSketch) Increment(elem interface{}) {
+	c.IncrementBy(elem, 1)
+}
+
+// IncrementBy counts n occurrences of the given elem.
+func (c *CountMinSketch) IncrementBy(elem interface{}, n uint64) {
+	a, b := hashElemPair(elem)
+	for i := uint64(0); i < c.depth; i++ {
+		c.counts[i*c.width+(a+i*b)%c.width] += n
+	}
+	c.total += n
+}
+
+// Estimate returns the estimated count of the given elem.
+func (c *CountMinSketch) Estimate(elem interface{}) uint64 {
+	a, b := hashElemPair(elem)
+	ret := uint64(math.MaxUint64)
+	for i := uint64(0); i < c.depth; i++ {
+		if n := c.counts[i*c.width+(a+i*b)%c.width]; n < ret {
+			ret = n
+		}
+	}
+	return ret
+}
+
+// Total returns the total count of all elements.
+func (c *CountMinSketch) Total() uint64 {
+	return c.total
+}
+
+// Width returns the number of counters in each row.
+func (c *CountMinSketch) Width() int {
+	return int(c.width)
+}
+
+// Depth returns the number of rows.
+func (c *CountMinSketch) Depth() int {
+	return int(c.depth)
+}
+
+// Merge adds the counts of the given sketch to this one, after that this
+// sketch estimates the counts of both streams. The two sketches must be
+// created with the same epsilon and delta.
+func (c *CountMinSketch) Merge(b *CountMinSketch) error {
+	if c.width != b.width || c.depth != b.depth {
+		return fmt.Errorf("error merge count-min sketches of different sizes: %dx%d and %dx%d", c.depth, c.width, b.depth, b.width)
+	}
+	for i, n := range b.counts {
+		c.counts[i] += n
+	}
+	c.total += b.total
+	return nil
+}
+
+// HeavyHitter is an element with its estimated count.
+type HeavyHitter struct {
+	Elem  interface{}
+	Count uint64
+}
+
+// TopK tracks the k most frequent elements of a stream on top of a
+// CountMinSketch. The counts are the estimates of the sketch, so they may
+// be greater than the true counts.
+//
+// Elements must be hashable. TopK is not thread safe.
+type TopK struct {
+	k      int
+	sketch *CountMinSketch
+	heap   hitterHeap
+}
+
+func newTopK(k int, epsilon, delta float64) *TopK {
+	if k < 1 {
+		panic(fmt.Sprintf("error create top-k tracker with k %d, it must be positive", k))
+	}
+	return &TopK{
+		k:      k,
+		sketch: newCountMinSketch(epsilon, delta),
+		heap:   hitterHeap{index: make(map[interface{}]int)},
+	}
+}
+
+// Add counts n occurrences of the given elem. It returns an
+// *UnhashableError if elem is unhashable.
+func (t *TopK) Add(elem interface{}, n uint64) error {
+	if !hashable(elem) {
+		return unhashableError(elem, 0)
+	}
+	t.sketch.IncrementBy(elem, n)
+	count := t.sketch.Estimate(elem)
+
+	h := &t.heap
+	if i, ok := h.index[elem]; ok {
+		h.hitters[i].Count = count
+		heap.Fix(h, i)
+		return nil
+	}
+	if h.Len() < t.k {
+		heap.Push(h, HeavyHitter{elem, count})
+		return nil
+	}
+	if count > h.hitters[0].Count {
+		delete(h.index, h.hitters[0].Elem)
+		h.hitters[0] = HeavyHitter{elem, count}
+		h.index[elem] = 0
+		heap.Fix(h, 0)
+	}
+	return nil
+}
+
+// Top returns the tracked heavy hitters in descending order of counts.
+func (t *TopK) Top() []HeavyHitter {
+	ret := make([]HeavyHitter, len(t.heap.hitters))
+	copy(ret, t.heap.hitters)
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Count > ret[j].Count
+	})
+	return ret
+}
+
+// Sketch returns the underlying CountMinSketch, which can estimate the
+// counts of all elements, not only the top k.
+func (t *TopK) Sketch() *CountMinSketch {
+	return t.sketch
+}
+
+// hitterHeap is a min heap of heavy hitters by counts, with the positions
+// of elements indexed.
+type hitterHeap struct {
+	hitters []HeavyHitter
+	index   map[interface{}]int
+}
+
+func (h *hitterHeap) Len() int {
+	return len(h.hitters)
+}
+
+func (h *hitterHeap) Less(i, j int) bool {
+	return h.hitters[i].Count < h.hitters[j].Count
+}
+
+func (h *hitterHeap) Swap(i, j int) {
+	h.hitters[i], h.hitters[j] = h.hitters[j], h.hitters[i]
+	h.index[h.hitters[i].Elem] = i
+	h.index[h.hitters[j].Elem] = j
+}
+
+func (h *hitterHeap) Push(x interface{}) {
+	hitter := x.(HeavyHitter)
+	h.index[hitter.Elem] = len(h.hitters)
+	h.hitters = append(h.hitters, hitter)
+}
+
+func (h *hitterHeap) Pop() interface{} {
+	last := h.hitters[len(h.hitters)-1]
+	h.hitters = h.hitters[:len(h.hitters)-1]
+	delete(h.index, last.Elem)
+	return last
+}
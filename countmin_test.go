@@ -0,0 +1,105 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_CountMinSketch_Estimate(t *testing.T) {
+	c := NewCountMinSketch(0.001, 0.01)
+	if c.Width() != 2719 || c.Depth() != 5 {
+		t.Errorf("NewCountMinSketch() = %dx%d, want 5x2719", c.Depth(), c.Width())
+	}
+	truth := map[interface{}]uint64{}
+	for i := 0; i < 20000; i++ {
+		elem := interface{}(i % 1000)
+		if i%3 == 0 {
+			elem = "s" + string(rune('a'+i%26))
+		}
+		c.Increment(elem)
+		truth[elem]++
+	}
+	c.IncrementBy(1.5, 7)
+	truth[1.5] += 7
+
+	bound := uint64(0.001 * float64(c.Total()))
+	for elem, want := range truth {
+		got := c.Estimate(elem)
+		if got < want || got > want+bound {
+			t.Errorf("CountMinSketch.Estimate(%v) = %v, want in [%v, %v]", elem, got, want, want+bound)
+		}
+	}
+	if c.Total() != 20007 {
+		t.Errorf("CountMinSketch.Total() = %v, want 20007", c.Total())
+	}
+	// 1 and "1" are in different typed buckets
+	if c.Estimate("1") > bound {
+		t.Errorf("CountMinSketch.Estimate(\"1\") = %v", c.Estimate("1"))
+	}
+}
+
+func Test_CountMinSketch_Merge(t *testing.T) {
+	a := NewCountMinSketch(0.01, 0.01)
+	b := NewCountMinSketch(0.01, 0.01)
+	a.IncrementBy("x", 3)
+	b.IncrementBy("x", 4)
+	b.Increment(2)
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("CountMinSketch.Merge() error = %v", err)
+	}
+	if a.Estimate("x") != 7 || a.Estimate(2) != 1 || a.Total() != 8 {
+		t.Errorf("CountMinSketch.Merge() = x:%v 2:%v total:%v", a.Estimate("x"), a.Estimate(2), a.Total())
+	}
+	if err := a.Merge(NewCountMinSketch(0.1, 0.01)); err == nil {
+		t.Errorf("CountMinSketch.Merge() different sizes should return error")
+	}
+}
+
+func Test_TopK(t *testing.T) {
+	top := NewTopK(3, 0.001, 0.01)
+	counts := []struct {
+		elem interface{}
+		n    uint64
+	}{
+		{"a", 50}, {"b", 10}, {1, 40}, {"c", 5}, {2.5, 30}, {"b", 5},
+	}
+	for _, c := range counts {
+		for i := uint64(0); i < c.n; i++ {
+			if err := top.Add(c.elem, 1); err != nil {
+				t.Fatalf("TopK.Add() error = %v", err)
+			}
+		}
+	}
+	want := []HeavyHitter{{"a", 50}, {1, 40}, {2.5, 30}}
+	got := top.Top()
+	if len(got) != len(want) {
+		t.Fatalf("TopK.Top() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("TopK.Top()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if top.Sketch().Estimate("b") != 15 {
+		t.Errorf("TopK.Sketch().Estimate(b) = %v", top.Sketch().Estimate("b"))
+	}
+	if err := top.Add([]int{1}, 1); !errors.Is(err, ErrUnhashable) {
+		t.Errorf("TopK.Add() unhashable error = %v", err)
+	}
+}
@@ -0,0 +1,464 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// GSet is a grow-only set, a conflict-free replicated data type (CRDT)
+// whose replicas converge by Merge in any order. Elements can not be
+// removed from it.
+//
+// The mutations since the last call of Delta are recorded, so that only
+// the changes are shipped to the other replicas, which merge them like
+// full states. Merged changes are not recorded.
+//
+// GSet is not thread safe.
+type GSet struct {
+	elems *set
+	delta *set
+}
+
+func newGSet(elems ...interface{}) *GSet {
+	s := &GSet{elems: newSet(), delta: newSet()}
+	if err := s.Add(elems...); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Add adds the elements to the set. Like Set.Add, nothing is added if any
+// of them is unhashable.
+func (s *GSet) Add(elems ...interface{}) error {
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		if !s.elems.Contains(elem) {
+			s.elems.Add(elem) //nolint:errcheck
+			s.delta.Add(elem) //nolint:errcheck
+		}
+	}
+	return nil
+}
+
+// Contains checks whether the given elem is in the set.
+func (s *GSet) Contains(elem interface{}) bool {
+	return s.elems.Contains(elem)
+}
+
+// Len returns the number of elements in the set.
+func (s *GSet) Len() int {
+	return s.elems.Len()
+}
+
+// Value returns the elements as a new thread unsafe Set.
+func (s *GSet) Value() Set {
+	return s.elems.Copy()
+}
+
+// Merge merges the state or the delta of another replica into s.
+func (s *GSet) Merge(b *GSet) {
+	s.elems.UniteWith(b.elems) //nolint:errcheck
+}
+
+// Delta returns the changes since the last call of Delta as a GSet, and
+// resets them.
+func (s *GSet) Delta() *GSet {
+	ret := &GSet{elems: s.delta, delta: newSet()}
+	s.delta = newSet()
+	return ret
+}
+
+// Copy returns a copy of the set, including the changes not taken by
+// Delta.
+func (s *GSet) Copy() *GSet {
+	return &GSet{elems: s.elems.Copy().(*set), delta: s.delta.Copy().(*set)}
+}
+
+// MarshalJSON implements json.Marshaler, the state is encoded like a Set.
+// The changes not taken by Delta are not encoded.
+func (s *GSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.elems)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it replaces the state of s.
+func (s *GSet) UnmarshalJSON(data []byte) error {
+	elems := newSet()
+	if err := json.Unmarshal(data, elems); err != nil {
+		return err
+	}
+	*s = GSet{elems: elems, delta: newSet()}
+	return nil
+}
+
+// TwoPhaseSet is a two-phase set (2P-Set) CRDT, which consists of a GSet
+// of added elements and a GSet of removed ones (tombstones). An element
+// is in the set if it is added and not removed, so a removed element can
+// never be added again.
+//
+// Like GSet, the mutations since the last call of Delta are recorded.
+//
+// TwoPhaseSet is not thread safe.
+type TwoPhaseSet struct {
+	added   *GSet
+	removed *GSet
+}
+
+func newTwoPhaseSet() *TwoPhaseSet {
+	return &TwoPhaseSet{added: newGSet(), removed: newGSet()}
+}
+
+// Add adds the elements to the set, adding a removed element has no
+// effect. Like Set.Add, nothing is added if any of them is unhashable.
+func (s *TwoPhaseSet) Add(elems ...interface{}) error {
+	return s.added.Add(elems...)
+}
+
+// Remove removes the elements in the set forever, the other elements are
+// ignored.
+func (s *TwoPhaseSet) Remove(elems ...interface{}) {
+	for _, elem := range elems {
+		if s.Contains(elem) {
+			s.removed.Add(elem) //nolint:errcheck
+		}
+	}
+}
+
+// Contains checks whether the given elem is in the set.
+func (s *TwoPhaseSet) Contains(elem interface{}) bool {
+	return s.added.Contains(elem) && !s.removed.Contains(elem)
+}
+
+// Len returns the number of elements in the set.
+func (s *TwoPhaseSet) Len() int {
+	// removed elements may be merged before they are added
+	n := 0
+	s.added.elems.Range(func(_ int, elem interface{}) bool {
+		if !s.removed.Contains(elem) {
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+// Value returns the elements as a new thread unsafe Set.
+func (s *TwoPhaseSet) Value() Set {
+	return s.added.elems.Diff(s.removed.elems)
+}
+
+// Merge merges the state or the delta of another replica into s.
+func (s *TwoPhaseSet) Merge(b *TwoPhaseSet) {
+	s.added.Merge(b.added)
+	s.removed.Merge(b.removed)
+}
+
+// Delta returns the changes since the last call of Delta as a
+// TwoPhaseSet, and resets them.
+func (s *TwoPhaseSet) Delta() *TwoPhaseSet {
+	return &TwoPhaseSet{added: s.added.Delta(), removed: s.removed.Delta()}
+}
+
+// Copy returns a copy of the set, including the changes not taken by
+// Delta.
+func (s *TwoPhaseSet) Copy() *TwoPhaseSet {
+	return &TwoPhaseSet{added: s.added.Copy(), removed: s.removed.Copy()}
+}
+
+type twoPhaseSetJSON struct {
+	Added   *GSet `json:"added"`
+	Removed *GSet `json:"removed"`
+}
+
+// MarshalJSON implements json.Marshaler, the added and removed elements
+// are encoded like Sets.
+func (s *TwoPhaseSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(twoPhaseSetJSON{Added: s.added, Removed: s.removed})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it replaces the state of s.
+func (s *TwoPhaseSet) UnmarshalJSON(data []byte) error {
+	v := twoPhaseSetJSON{Added: newGSet(), Removed: newGSet()}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = TwoPhaseSet{added: v.Added, removed: v.Removed}
+	return nil
+}
+
+// Dot identifies an add operation of an ORSet, which is the Counter-th
+// add of the replica.
+type Dot struct {
+	Replica string `json:"replica"`
+	Counter uint64 `json:"counter"`
+}
+
+// ORSet is an observed-remove set (OR-Set) CRDT. Each add is tagged by a
+// unique Dot, and a remove only removes the dots it observed. So an
+// element removed by a replica is still in the set if it is added by
+// another replica concurrently (add wins), and it can be added again.
+//
+// The dots of removed elements are kept as tombstones, so the state grows
+// with the number of removes. Like GSet, the mutations since the last call
+// of Delta are recorded.
+//
+// ORSet is not thread safe.
+type ORSet struct {
+	replica string
+	clock   uint64
+	// live dots of elements
+	adds map[interface{}]map[Dot]struct{}
+	// the elements of live dots
+	dots    map[Dot]interface{}
+	removed map[Dot]struct{}
+	delta   *ORSet
+}
+
+func newORSet(replica string) *ORSet {
+	return &ORSet{
+		replica: replica,
+		adds:    make(map[interface{}]map[Dot]struct{}),
+		dots:    make(map[Dot]interface{}),
+		removed: make(map[Dot]struct{}),
+	}
+}
+
+// Replica returns the ID of the replica.
+func (s *ORSet) Replica() string {
+	return s.replica
+}
+
+func (s *ORSet) recordDelta() *ORSet {
+	if s.delta == nil {
+		s.delta = newORSet(s.replica)
+	}
+	return s.delta
+}
+
+// observe advances the clock past the dots of this replica, so that new
+// dots never collide with the merged ones.
+func (s *ORSet) observe(dot Dot) {
+	if dot.Replica == s.replica && dot.Counter > s.clock {
+		s.clock = dot.Counter
+	}
+}
+
+// addDot adds the dot of elem unless it is removed.
+func (s *ORSet) addDot(elem interface{}, dot Dot) {
+	s.observe(dot)
+	if _, ok := s.removed[dot]; ok {
+		return
+	}
+	if _, ok := s.dots[dot]; ok {
+		return
+	}
+	dots, ok := s.adds[elem]
+	if !ok {
+		dots = make(map[Dot]struct{})
+		s.adds[elem] = dots
+	}
+	dots[dot] = struct{}{}
+	s.dots[dot] = elem
+}
+
+// removeDot adds the dot to the tombstones.
+func (s *ORSet) removeDot(dot Dot) {
+	s.observe(dot)
+	s.removed[dot] = struct{}{}
+	elem, ok := s.dots[dot]
+	if !ok {
+		return
+	}
+	delete(s.dots, dot)
+	delete(s.adds[elem], dot)
+	if len(s.adds[elem]) == 0 {
+		delete(s.adds, elem)
+	}
+}
+
+// Add adds the elements to the set with new dots. Like Set.Add, nothing
+// is added if any of them is unhashable.
+func (s *ORSet) Add(elems ...interface{}) error {
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
+	delta := s.recordDelta()
+	for _, elem := range elems {
+		s.clock++
+		dot := Dot{Replica: s.replica, Counter: s.clock}
+		s.addDot(elem, dot)
+		delta.addDot(elem, dot)
+	}
+	return nil
+}
+
+// Remove removes the elements by the dots observed by this replica.
+func (s *ORSet) Remove(elems ...interface{}) {
+	delta := s.recordDelta()
+	for _, elem := range elems {
+		if !hashable(elem) {
+			continue
+		}
+		for dot := range s.adds[elem] {
+			s.removeDot(dot)
+			delta.removeDot(dot)
+		}
+	}
+}
+
+// Contains checks whether the given elem is in the set.
+func (s *ORSet) Contains(elem interface{}) bool {
+	if !hashable(elem) {
+		return false
+	}
+	_, ok := s.adds[elem]
+	return ok
+}
+
+// Len returns the number of elements in the set.
+func (s *ORSet) Len() int {
+	return len(s.adds)
+}
+
+// Value returns the elements as a new thread unsafe Set.
+func (s *ORSet) Value() Set {
+	ret := newSet()
+	for elem := range s.adds {
+		ret.Add(elem) //nolint:errcheck
+	}
+	return ret
+}
+
+// Merge merges the state or the delta of another replica into s.
+func (s *ORSet) Merge(b *ORSet) {
+	for dot := range b.removed {
+		s.removeDot(dot)
+	}
+	for elem, dots := range b.adds {
+		for dot := range dots {
+			s.addDot(elem, dot)
+		}
+	}
+}
+
+// Delta returns the changes since the last call of Delta as an ORSet,
+// and resets them.
+func (s *ORSet) Delta() *ORSet {
+	ret := s.recordDelta()
+	s.delta = nil
+	return ret
+}
+
+// Copy returns a copy of the set, including the changes not taken by
+// Delta.
+func (s *ORSet) Copy() *ORSet {
+	ret := newORSet(s.replica)
+	ret.Merge(s)
+	ret.clock = s.clock
+	if s.delta != nil {
+		ret.delta = s.delta.Copy()
+	}
+	return ret
+}
+
+type orSetJSON struct {
+	Replica  string          `json:"replica"`
+	Clock    uint64          `json:"clock"`
+	Elements json.RawMessage `json:"elements"`
+	// dots of elements in the same order
+	Dots    [][]Dot `json:"dots"`
+	Removed []Dot   `json:"removed"`
+}
+
+func sortDots(dots []Dot) {
+	sort.Slice(dots, func(i, j int) bool {
+		if dots[i].Replica != dots[j].Replica {
+			return dots[i].Replica < dots[j].Replica
+		}
+		return dots[i].Counter < dots[j].Counter
+	})
+}
+
+// MarshalJSON implements json.Marshaler. The elements are encoded like a
+// Set, with their dots and the tombstones. The changes not taken by Delta
+// are not encoded.
+func (s *ORSet) MarshalJSON() ([]byte, error) {
+	elems := make([]interface{}, 0, len(s.adds))
+	for elem := range s.adds {
+		elems = append(elems, elem)
+	}
+	// the order is kept by marshalElements and unmarshalElements
+	sortElements(elems)
+	data, err := marshalElements(elems)
+	if err != nil {
+		return nil, err
+	}
+	v := orSetJSON{
+		Replica:  s.replica,
+		Clock:    s.clock,
+		Elements: data,
+		Dots:     make([][]Dot, len(elems)),
+		Removed:  make([]Dot, 0, len(s.removed)),
+	}
+	for i, elem := range elems {
+		for dot := range s.adds[elem] {
+			v.Dots[i] = append(v.Dots[i], dot)
+		}
+		sortDots(v.Dots[i])
+	}
+	for dot := range s.removed {
+		v.Removed = append(v.Removed, dot)
+	}
+	sortDots(v.Removed)
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it replaces the state of s.
+func (s *ORSet) UnmarshalJSON(data []byte) error {
+	v := orSetJSON{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	elems, err := unmarshalElements(v.Elements)
+	if err != nil {
+		return err
+	}
+	if len(elems) != len(v.Dots) {
+		return fmt.Errorf("goset: %d elements with %d dots in ORSet", len(elems), len(v.Dots))
+	}
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
+	ret := newORSet(v.Replica)
+	for _, dot := range v.Removed {
+		ret.removeDot(dot)
+	}
+	for i, elem := range elems {
+		for _, dot := range v.Dots[i] {
+			ret.addDot(elem, dot)
+		}
+	}
+	if v.Clock > ret.clock {
+		ret.clock = v.Clock
+	}
+	*s = *ret
+	return nil
+}
@@ -0,0 +1,318 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+// crdt adapts the CRDT sets for the property tests.
+type crdt interface {
+	mutate(r *rand.Rand)
+	merge(b crdt)
+	copy() crdt
+	delta() crdt
+	value() Set
+	// state returns the state shared by replicas
+	state() string
+	roundTrip() (crdt, error)
+}
+
+func randomElem(r *rand.Rand) interface{} {
+	if r.Intn(2) == 0 {
+		return r.Intn(8)
+	}
+	return string(rune('a' + r.Intn(8)))
+}
+
+type gsetCRDT struct{ *GSet }
+
+func (s gsetCRDT) mutate(r *rand.Rand) { s.Add(randomElem(r)) } //nolint:errcheck
+func (s gsetCRDT) merge(b crdt)        { s.Merge(b.(gsetCRDT).GSet) }
+func (s gsetCRDT) copy() crdt          { return gsetCRDT{s.Copy()} }
+func (s gsetCRDT) delta() crdt         { return gsetCRDT{s.Delta()} }
+func (s gsetCRDT) value() Set          { return s.Value() }
+func (s gsetCRDT) state() string       { return mustMarshal(s.GSet) }
+func (s gsetCRDT) roundTrip() (crdt, error) {
+	ret := NewGSet()
+	return gsetCRDT{ret}, json.Unmarshal([]byte(mustMarshal(s.GSet)), ret)
+}
+
+type twoPhaseCRDT struct{ *TwoPhaseSet }
+
+func (s twoPhaseCRDT) mutate(r *rand.Rand) {
+	if r.Intn(3) == 0 {
+		s.Remove(randomElem(r))
+		return
+	}
+	s.Add(randomElem(r)) //nolint:errcheck
+}
+func (s twoPhaseCRDT) merge(b crdt)  { s.Merge(b.(twoPhaseCRDT).TwoPhaseSet) }
+func (s twoPhaseCRDT) copy() crdt    { return twoPhaseCRDT{s.Copy()} }
+func (s twoPhaseCRDT) delta() crdt   { return twoPhaseCRDT{s.Delta()} }
+func (s twoPhaseCRDT) value() Set    { return s.Value() }
+func (s twoPhaseCRDT) state() string { return mustMarshal(s.TwoPhaseSet) }
+func (s twoPhaseCRDT) roundTrip() (crdt, error) {
+	ret := NewTwoPhaseSet()
+	return twoPhaseCRDT{ret}, json.Unmarshal([]byte(mustMarshal(s.TwoPhaseSet)), ret)
+}
+
+type orCRDT struct{ *ORSet }
+
+func (s orCRDT) mutate(r *rand.Rand) {
+	if r.Intn(3) == 0 {
+		s.Remove(randomElem(r))
+		return
+	}
+	s.Add(randomElem(r)) //nolint:errcheck
+}
+func (s orCRDT) merge(b crdt) { s.Merge(b.(orCRDT).ORSet) }
+func (s orCRDT) copy() crdt   { return orCRDT{s.Copy()} }
+func (s orCRDT) delta() crdt  { return orCRDT{s.Delta()} }
+func (s orCRDT) value() Set   { return s.Value() }
+func (s orCRDT) state() string {
+	// the replica and clock are local to the replica
+	v := orSetJSON{}
+	json.Unmarshal([]byte(mustMarshal(s.ORSet)), &v) //nolint:errcheck
+	v.Replica, v.Clock = "", 0
+	return mustMarshal(v)
+}
+func (s orCRDT) roundTrip() (crdt, error) {
+	ret := NewORSet("")
+	return orCRDT{ret}, json.Unmarshal([]byte(mustMarshal(s.ORSet)), ret)
+}
+
+func mustMarshal(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+var crdtKinds = []struct {
+	name string
+	new  func(replica string) crdt
+}{
+	{"GSet", func(string) crdt { return gsetCRDT{NewGSet()} }},
+	{"TwoPhaseSet", func(string) crdt { return twoPhaseCRDT{NewTwoPhaseSet()} }},
+	{"ORSet", func(replica string) crdt { return orCRDT{NewORSet(replica)} }},
+}
+
+// randomReplicas returns three replicas mutated randomly, they share some
+// history by merging each other sometimes.
+func randomReplicas(r *rand.Rand, newCRDT func(string) crdt) []crdt {
+	replicas := []crdt{newCRDT("a"), newCRDT("b"), newCRDT("c")}
+	for i := 0; i < 30; i++ {
+		x := replicas[r.Intn(3)]
+		if r.Intn(5) == 0 {
+			x.merge(replicas[r.Intn(3)].copy())
+			continue
+		}
+		x.mutate(r)
+	}
+	return replicas
+}
+
+func merged(a, b crdt) crdt {
+	ret := a.copy()
+	ret.merge(b)
+	return ret
+}
+
+func Test_CRDT_MergeProperties(t *testing.T) {
+	for i := range crdtKinds {
+		kind := crdtKinds[i]
+		t.Run(kind.name, func(t *testing.T) {
+			for seed := int64(0); seed < 200; seed++ {
+				r := rand.New(rand.NewSource(seed))
+				replicas := randomReplicas(r, kind.new)
+				a, b, c := replicas[0], replicas[1], replicas[2]
+
+				if x, y := merged(a, b), merged(b, a); x.state() != y.state() || !x.value().Equal(y.value()) {
+					t.Fatalf("seed %d: merge is not commutative: %v != %v", seed, x.state(), y.state())
+				}
+				if x, y := merged(merged(a, b), c), merged(a, merged(b, c)); x.state() != y.state() || !x.value().Equal(y.value()) {
+					t.Fatalf("seed %d: merge is not associative: %v != %v", seed, x.state(), y.state())
+				}
+				if x := merged(a, a); x.state() != a.state() || !x.value().Equal(a.value()) {
+					t.Fatalf("seed %d: merge is not idempotent: %v != %v", seed, x.state(), a.state())
+				}
+				if x := merged(merged(a, b), b); x.state() != merged(a, b).state() {
+					t.Fatalf("seed %d: merge is not idempotent: %v", seed, x.state())
+				}
+			}
+		})
+	}
+}
+
+func Test_CRDT_Delta(t *testing.T) {
+	for i := range crdtKinds {
+		kind := crdtKinds[i]
+		t.Run(kind.name, func(t *testing.T) {
+			for seed := int64(0); seed < 100; seed++ {
+				r := rand.New(rand.NewSource(seed))
+				x := kind.new("x")
+				deltas := []crdt{}
+				for round := 0; round < 10; round++ {
+					for j := r.Intn(5); j > 0; j-- {
+						x.mutate(r)
+					}
+					deltas = append(deltas, x.delta())
+				}
+
+				// deltas merged in any order converge to the state
+				inOrder, shuffled := kind.new("y"), kind.new("z")
+				for _, d := range deltas {
+					inOrder.merge(d)
+				}
+				r.Shuffle(len(deltas), func(i, j int) {
+					deltas[i], deltas[j] = deltas[j], deltas[i]
+				})
+				for _, d := range deltas {
+					shuffled.merge(d)
+				}
+				if inOrder.state() != x.state() || shuffled.state() != x.state() {
+					t.Fatalf("seed %d: deltas converge to %v and %v, want %v", seed, inOrder.state(), shuffled.state(), x.state())
+				}
+				if x.delta().value().Len() != 0 {
+					t.Fatalf("seed %d: Delta() does not reset the changes", seed)
+				}
+			}
+		})
+	}
+}
+
+func Test_CRDT_JSON(t *testing.T) {
+	for i := range crdtKinds {
+		kind := crdtKinds[i]
+		t.Run(kind.name, func(t *testing.T) {
+			for seed := int64(0); seed < 50; seed++ {
+				r := rand.New(rand.NewSource(seed))
+				for _, x := range randomReplicas(r, kind.new) {
+					got, err := x.roundTrip()
+					if err != nil {
+						t.Fatalf("seed %d: round trip error = %v", seed, err)
+					}
+					if got.state() != x.state() || !got.value().Equal(x.value()) {
+						t.Fatalf("seed %d: round trip = %v, want %v", seed, got.state(), x.state())
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_GSet(t *testing.T) {
+	s := NewGSet(1, "a")
+	if err := s.Add([]int{1}); err == nil {
+		t.Errorf("Add() unhashable error = nil")
+	}
+	s.Add(1, 2) //nolint:errcheck
+	if s.Len() != 3 || !s.Contains(2) || !s.Value().Equal(NewSet(1, 2, "a")) {
+		t.Errorf("GSet = %v", s.Value())
+	}
+	if got := s.Delta().Value(); !got.Equal(NewSet(1, 2, "a")) {
+		t.Errorf("Delta() = %v", got)
+	}
+	s.Value().Add(3) //nolint:errcheck
+	if s.Contains(3) {
+		t.Errorf("Value() returns the state itself")
+	}
+}
+
+func Test_TwoPhaseSet(t *testing.T) {
+	a, b := NewTwoPhaseSet(), NewTwoPhaseSet()
+	a.Add(1, 2) //nolint:errcheck
+	a.Remove(1, 3)
+	if a.Len() != 1 || a.Contains(1) || !a.Contains(2) {
+		t.Errorf("TwoPhaseSet = %v", a.Value())
+	}
+	a.Add(1) //nolint:errcheck
+	if a.Contains(1) {
+		t.Errorf("a removed element is added again")
+	}
+
+	// the remove is merged before the add
+	b.Merge(a.Delta())
+	if b.Len() != 1 || !b.Value().Equal(NewSet(2)) {
+		t.Errorf("merged TwoPhaseSet = %v, Len() = %v", b.Value(), b.Len())
+	}
+	onlyRemoved := &TwoPhaseSet{added: NewGSet(), removed: NewGSet(2)}
+	b = NewTwoPhaseSet()
+	b.Merge(onlyRemoved)
+	b.Add(2) //nolint:errcheck
+	if b.Len() != 0 || b.Contains(2) {
+		t.Errorf("TwoPhaseSet with an early tombstone, Len() = %v", b.Len())
+	}
+}
+
+func Test_ORSet(t *testing.T) {
+	a, b := NewORSet("a"), NewORSet("b")
+	a.Add("x", "y") //nolint:errcheck
+	b.Merge(a)
+
+	// concurrent remove and add, add wins
+	b.Remove("x")
+	a.Add("x") //nolint:errcheck
+	a.Merge(b)
+	b.Merge(a)
+	if !a.Contains("x") || !b.Contains("x") {
+		t.Errorf("concurrent add does not win, a = %v, b = %v", a.Value(), b.Value())
+	}
+
+	// observed remove
+	a.Remove("x", "missing")
+	b.Merge(a.Delta())
+	if b.Contains("x") || !b.Value().Equal(NewSet("y")) {
+		t.Errorf("observed remove is not merged, b = %v", b.Value())
+	}
+	b.Add("x") //nolint:errcheck
+	if !b.Contains("x") || b.Len() != 2 {
+		t.Errorf("a removed element can not be added again, b = %v", b.Value())
+	}
+	if a.Replica() != "a" {
+		t.Errorf("Replica() = %v", a.Replica())
+	}
+	if err := a.Add(map[int]int{}); err == nil || a.Contains([]int{}) {
+		t.Errorf("Add() unhashable error = %v", err)
+	}
+}
+
+func Test_ORSet_Restore(t *testing.T) {
+	a := NewORSet("a")
+	a.Add(1, 2) //nolint:errcheck
+	a.Remove(2)
+	data, _ := json.Marshal(a)
+
+	// a replica restored from the state never reuses the dots
+	restored := NewORSet("")
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	restored.Add(3) //nolint:errcheck
+	for dot := range restored.adds[3] {
+		if dot.Counter <= 2 || dot.Replica != "a" {
+			t.Errorf("restored replica adds with dot %v", dot)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"elements":[1],"dots":[]}`), restored); err == nil {
+		t.Errorf("Unmarshal() with mismatched dots error = nil")
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	cuckooBucketSize = 4
+	cuckooMaxKicks   = 500
+	cuckooLoadFactor = 0.95
+)
+
+// ErrFilterFull is returned by CuckooFilter.Add if no room can be made for
+// the element, the filter is left unchanged.
+var ErrFilterFull = errors.New("goset: cuckoo filter is full")
+
+// CuckooFilter is a probabilistic set of elements which supports removal.
+// Like BloomFilter, it may report that an element is in the filter while
+// it is not, but never reports that an added element is not in the filter.
+//
+// Each element is stored as a fingerprint in one of its two candidate
+// buckets of 4 slots. The false positive rate is about 8/2^fingerprintBits,
+// e.g. 0.2% for 12-bit fingerprints.
+//
+// Adding an element twice stores two fingerprints, and Remove removes one
+// of them. Only remove elements which have been added, otherwise another
+// element with the same fingerprint may be removed instead.
+//
+// CuckooFilter is not thread safe.
+type CuckooFilter struct {
+	slots  []uint16
+	mask   uint64
+	fpBits uint
+	count  int
+	rand   uint64
+}
+
+func newCuckooFilter(capacity, fingerprintBits int) *CuckooFilter {
+	if fingerprintBits < 4 || fingerprintBits > 16 {
+		panic(fmt.Sprintf("error create cuckoo filter with %d-bit fingerprints, it must be in [4, 16]", fingerprintBits))
+	}
+	buckets := uint64(1)
+	want := uint64(math.Ceil(float64(capacity) / cuckooBucketSize / cuckooLoadFactor))
+	for buckets < want {
+		buckets <<= 1
+	}
+	return &CuckooFilter{
+		slots:  make([]uint16, buckets*cuckooBucketSize),
+		mask:   buckets - 1,
+		fpBits: uint(fingerprintBits),
+		rand:   goldenRatio64,
+	}
+}
+
+// fingerprint returns the fingerprint and the first candidate bucket of
+// elem. Fingerprints are never zero, zero marks empty slots.
+func (f *CuckooFilter) fingerprint(elem interface{}) (uint16, uint64) {
+	a, b := hashElemPair(elem)
+	fp := uint16(b >> (64 - f.fpBits))
+	if fp == 0 {
+		fp = 1
+	}
+	return fp, a & f.mask
+}
+
+// altIndex returns the other candidate bucket of a fingerprint in bucket i.
+func (f *CuckooFilter) altIndex(i uint64, fp uint16) uint64 {
+	return (i ^ mix64(uint64(fp))) & f.mask
+}
+
+func (f *CuckooFilter) bucket(i uint64) []uint16 {
+	return f.slots[i*cuckooBucketSize : (i+1)*cuckooBucketSize]
+}
+
+func (f *CuckooFilter) insert(i uint64, fp uint16) bool {
+	b := f.bucket(i)
+	for j := range b {
+		if b[j] == 0 {
+			b[j] = fp
+			return true
+		}
+	}
+	return false
+}
+
+func (f *CuckooFilter) nextRand() uint64 {
+	f.rand ^= f.rand << 13
+	f.rand ^= f.rand >> 7
+	f.rand ^= f.rand << 17
+	return f.rand
+}
+
+// Add adds the given elem to the filter. It returns ErrFilterFull if there
+// is no room for the elem, and the filter is left unchanged.
+func (f *CuckooFilter) Add(elem interface{}) error {
+	fp, i1 := f.fingerprint(elem)
+	i2 := f.altIndex(i1, fp)
+	if f.insert(i1, fp) || f.insert(i2, fp) {
+		f.count++
+		return nil
+	}
+
+	// kick out fingerprints to their alternate buckets, remember the path
+	// to undo it if no room is found
+	type kick struct {
+		slot int
+		fp   uint16
+	}
+	path := make([]kick, 0, 16)
+	i := i1
+	if f.nextRand()&1 == 1 {
+		i = i2
+	}
+	for n := 0; n < cuckooMaxKicks; n++ {
+		slot := int(i)*cuckooBucketSize + int(f.nextRand()%cuckooBucketSize)
+		path = append(path, kick{slot, f.slots[slot]})
+		fp, f.slots[slot] = f.slots[slot], fp
+		i = f.altIndex(i, fp)
+		if f.insert(i, fp) {
+			f.count++
+			return nil
+		}
+	}
+	for j := len(path) - 1; j >= 0; j-- {
+		f.slots[path[j].slot] = path[j].fp
+	}
+	return ErrFilterFull
+}
+
+// Remove removes one fingerprint of the given elem from the filter, and
+// reports whether it is found.
+func (f *CuckooFilter) Remove(elem interface{}) bool {
+	fp, i1 := f.fingerprint(elem)
+	for _, i := range []uint64{i1, f.altIndex(i1, fp)} {
+		b := f.bucket(i)
+		for j := range b {
+			if b[j] == fp {
+				b[j] = 0
+				f.count--
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MayContain reports whether the given elem may be in the filter. It
+// returns false only if elem has not been added or has been removed.
+func (f *CuckooFilter) MayContain(elem interface{}) bool {
+	fp, i1 := f.fingerprint(elem)
+	for _, i := range []uint64{i1, f.altIndex(i1, fp)} {
+		for _, x := range f.bucket(i) {
+			if x == fp {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Len returns the number of fingerprints stored in the filter.
+func (f *CuckooFilter) Len() int {
+	return f.count
+}
+
+// Cap returns the number of slots in the filter, the filter usually gets
+// full when about 95% of slots are used.
+func (f *CuckooFilter) Cap() int {
+	return len(f.slots)
+}
+
+// FingerprintBits returns the size of fingerprints in bits.
+func (f *CuckooFilter) FingerprintBits() int {
+	return int(f.fpBits)
+}
+
+// AddSet adds all elements in the given Set to the filter. It stops at the
+// first error and returns it, the elements added before are kept.
+func (f *CuckooFilter) AddSet(s Set) error {
+	var err error
+	s.Range(func(_ int, elem interface{}) bool {
+		err = f.Add(elem)
+		return err == nil
+	})
+	return err
+}
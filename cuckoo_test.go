@@ -0,0 +1,124 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func Test_CuckooFilter_Add_Remove(t *testing.T) {
+	f := NewCuckooFilter(1000, 12)
+	for i := 0; i < 1000; i++ {
+		if err := f.Add(i); err != nil {
+			t.Fatalf("CuckooFilter.Add(%d) error = %v", i, err)
+		}
+	}
+	if f.Len() != 1000 {
+		t.Errorf("CuckooFilter.Len() = %v, want 1000", f.Len())
+	}
+	for i := 0; i < 1000; i++ {
+		if !f.MayContain(i) {
+			t.Fatalf("CuckooFilter.MayContain(%d) = false for added element", i)
+		}
+	}
+
+	fp := 0
+	for i := 0; i < 10000; i++ {
+		if f.MayContain(fmt.Sprintf("x%d", i)) {
+			fp++
+		}
+	}
+	if fp > 50 {
+		t.Errorf("false positive rate = %v, want about 0.002", float64(fp)/10000)
+	}
+
+	for i := 0; i < 1000; i += 2 {
+		if !f.Remove(i) {
+			t.Fatalf("CuckooFilter.Remove(%d) = false", i)
+		}
+	}
+	if f.Len() != 500 {
+		t.Errorf("CuckooFilter.Len() = %v, want 500", f.Len())
+	}
+	for i := 1; i < 1000; i += 2 {
+		if !f.MayContain(i) {
+			t.Fatalf("CuckooFilter.MayContain(%d) = false after removing others", i)
+		}
+	}
+	removed := 0
+	for i := 0; i < 1000; i += 2 {
+		if !f.MayContain(i) {
+			removed++
+		}
+	}
+	if removed < 490 {
+		t.Errorf("only %d of 500 removed elements are gone", removed)
+	}
+}
+
+func Test_CuckooFilter_Full(t *testing.T) {
+	f := NewCuckooFilter(8, 8)
+	var err error
+	added := 0
+	for i := 0; i < 100 && err == nil; i++ {
+		if err = f.Add(i); err == nil {
+			added++
+		}
+	}
+	if !errors.Is(err, ErrFilterFull) {
+		t.Fatalf("CuckooFilter.Add() error = %v, want ErrFilterFull", err)
+	}
+	if f.Len() != added || added > f.Cap() {
+		t.Errorf("CuckooFilter.Len() = %v, added %v, cap %v", f.Len(), added, f.Cap())
+	}
+	// a failed Add must not lose the elements already added
+	for i := 0; i < added; i++ {
+		if !f.MayContain(i) {
+			t.Errorf("CuckooFilter.MayContain(%d) = false after filter is full", i)
+		}
+	}
+}
+
+func Test_CuckooFilter_FromSet(t *testing.T) {
+	s := NewSet(1, 2, "a", 3.5)
+	f, err := NewCuckooFilterFrom(s, 16)
+	if err != nil {
+		t.Fatalf("NewCuckooFilterFrom() error = %v", err)
+	}
+	if f.Len() != 4 || f.FingerprintBits() != 16 {
+		t.Errorf("NewCuckooFilterFrom() = %d elements %d bits", f.Len(), f.FingerprintBits())
+	}
+	for _, elem := range s.Elements() {
+		if !f.MayContain(elem) {
+			t.Errorf("CuckooFilter.MayContain(%v) = false", elem)
+		}
+	}
+	if f.Remove("b") {
+		t.Errorf("CuckooFilter.Remove() missing element = true")
+	}
+}
+
+func Test_NewCuckooFilter_Panic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewCuckooFilter() with 20-bit fingerprints should panic")
+		}
+	}()
+	NewCuckooFilter(10, 20)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUnhashable is returned when an element can not be used as a key of
+// hash table, such as functions, maps, slices, and structs, arrays or
+// interfaces holding them. Use errors.Is to check it, the actual error
+// is an *UnhashableError.
+var ErrUnhashable = errors.New("goset: unhashable element")
+
+// UnhashableError records an unhashable element and where it is given.
+type UnhashableError struct {
+	// Value is the unhashable element.
+	Value interface{}
+	// Type is the dynamic type of the element.
+	Type reflect.Type
+	// Index is the index of the element in the arguments of Add, or in
+	// the collection given to Extend.
+	Index int
+}
+
+func (e *UnhashableError) Error() string {
+	return fmt.Sprintf("%v: %v of type %v at index %d", ErrUnhashable, e.Value, e.Type, e.Index)
+}
+
+// Unwrap returns ErrUnhashable.
+func (e *UnhashableError) Unwrap() error {
+	return ErrUnhashable
+}
+
+func unhashableError(elem interface{}, index int) error {
+	return &UnhashableError{
+		Value: elem,
+		Type:  reflect.TypeOf(elem),
+		Index: index,
+	}
+}
+
+// checkHashable returns an *UnhashableError for the first unhashable
+// element in elems.
+func checkHashable(elems []interface{}) error {
+	for i, elem := range elems {
+		if !hashable(elem) {
+			return unhashableError(elem, i)
+		}
+	}
+	return nil
+}
+
+// checkSetHashable is like checkHashable, but checks the elements of the
+// thread unsafe set b, the index is the one given by Range.
+func checkSetHashable(b Set) error {
+	var err error
+	b.Range(func(i int, elem interface{}) bool {
+		if !hashable(elem) {
+			err = unhashableError(elem, i)
+		}
+		return err == nil
+	})
+	return err
+}
+
+// hashable reports whether elem can be used as a map key without panic.
+// Types which are comparable may still hold unhashable values in their
+// interface fields, so the values are checked too.
+func hashable(elem interface{}) bool {
+	switch elem.(type) {
+	case nil, int, string:
+		return true
+	}
+	return hashableValue(reflect.ValueOf(elem))
+}
+
+func hashableValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Func, reflect.Map, reflect.Slice:
+		return false
+	case reflect.Interface:
+		return v.IsNil() || hashableValue(v.Elem())
+	case reflect.Array:
+		if !v.Type().Comparable() {
+			return false
+		}
+		if !holdsInterface(v.Type()) {
+			return true
+		}
+		for i := 0; i < v.Len(); i++ {
+			if !hashableValue(v.Index(i)) {
+				return false
+			}
+		}
+	case reflect.Struct:
+		if !v.Type().Comparable() {
+			return false
+		}
+		if !holdsInterface(v.Type()) {
+			return true
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if !hashableValue(v.Field(i)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// holdsInterface reports whether values of type t may hold interfaces
+// without indirection.
+func holdsInterface(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Array:
+		return holdsInterface(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if holdsInterface(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_hashable(t *testing.T) {
+	type plain struct {
+		A int
+		B string
+	}
+	type holder struct {
+		A int
+		V interface{}
+	}
+	tests := []struct {
+		name string
+		elem interface{}
+		want bool
+	}{
+		{"nil", nil, true},
+		{"int", 1, true},
+		{"float", 1.5, true},
+		{"pointer", &plain{}, true},
+		{"struct", plain{1, "a"}, true},
+		{"slice", []int{1}, false},
+		{"map", map[int]int{}, false},
+		{"func", func() {}, false},
+		{"struct holding int", holder{1, 2}, true},
+		{"struct holding nil", holder{1, nil}, true},
+		{"struct holding slice", holder{1, []int{1}}, false},
+		{"array holding map", [2]interface{}{1, map[int]int{}}, false},
+		{"nested", holder{1, holder{2, [1]interface{}{[]string{}}}}, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hashable(tt.elem); got != tt.want {
+				t.Errorf("hashable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_UnhashableError(t *testing.T) {
+	sets := []struct {
+		name string
+		s    Set
+	}{
+		{"set", NewSet(1, 2)},
+		{"safe", NewSafeSet(1, 2)},
+		{"ordered", NewOrderedSet(1, 2)},
+		{"bitmap", NewBitmapSet(1, 2)},
+		{"sharded", NewShardedSet(4, 1, 2)},
+	}
+	type holder struct {
+		V interface{}
+	}
+	for i := range sets {
+		tt := sets[i]
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.s.Add(3, 4, holder{[]int{1}})
+			if !errors.Is(err, ErrUnhashable) {
+				t.Fatalf("Add() error = %v, want ErrUnhashable", err)
+			}
+			var e *UnhashableError
+			if !errors.As(err, &e) {
+				t.Fatalf("Add() error = %T, want *UnhashableError", err)
+			}
+			if e.Index != 2 || e.Type != reflect.TypeOf(holder{}) {
+				t.Errorf("UnhashableError = %+v", e)
+			}
+			if tt.s.Len() != 2 || tt.s.Contains(3) {
+				t.Errorf("Add() is not all-or-nothing, got %v", tt.s)
+			}
+
+			err = tt.s.Extend([]interface{}{5, map[int]int{}})
+			if !errors.As(err, &e) || e.Index != 1 {
+				t.Errorf("Extend() error = %v, want index 1", err)
+			}
+			if tt.s.Len() != 2 || tt.s.Contains(5) {
+				t.Errorf("Extend() is not all-or-nothing, got %v", tt.s)
+			}
+
+			if ok, err := tt.s.ContainsErr([]int{1}); ok || !errors.Is(err, ErrUnhashable) {
+				t.Errorf("ContainsErr() = %v, %v", ok, err)
+			}
+			if ok, err := tt.s.ContainsErr(1); !ok || err != nil {
+				t.Errorf("ContainsErr() = %v, %v", ok, err)
+			}
+			if tt.s.Contains([]int{1}) {
+				t.Errorf("Contains() = true")
+			}
+			tt.s.Remove([]int{1}, 1)
+			if tt.s.Len() != 1 {
+				t.Errorf("Remove() got %v", tt.s)
+			}
+		})
+	}
+}
+
+func Test_sortedSet_ContainsErr(t *testing.T) {
+	s := NewSortedSet(1, "a")
+	var e *UnhashableError
+	if ok, err := s.ContainsErr([]int{1}); ok || !errors.As(err, &e) {
+		t.Errorf("sortedSet.ContainsErr() = %v, %v, want *UnhashableError", ok, err)
+	}
+	// elements which can not be ordered are never in the set
+	if ok, err := s.ContainsErr(1.5); ok || err != nil {
+		t.Errorf("sortedSet.ContainsErr() = %v, %v", ok, err)
+	}
+	if ok, err := s.ContainsErr("a"); !ok || err != nil {
+		t.Errorf("sortedSet.ContainsErr() = %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,583 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExprError records an error in parsing or evaluating a set expression and
+// where it occurs.
+type ExprError struct {
+	// Expr is the source of the expression.
+	Expr string
+	// Pos is the byte offset in Expr where the error occurs.
+	Pos int
+	// Msg describes the error.
+	Msg string
+}
+
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("goset: %s at position %d in %q", e.Msg, e.Pos, e.Expr)
+}
+
+type exprOp int
+
+const (
+	exprName exprOp = iota
+	exprLiteral
+	exprUnion
+	exprSymmetricDiff
+	exprIntersect
+	exprDiff
+)
+
+// exprOps are the binary operators from the lowest precedence to the
+// highest, the same as Python.
+var exprOps = []struct {
+	sym  byte
+	op   exprOp
+	name string
+}{
+	{'|', exprUnion, "union"},
+	{'^', exprSymmetricDiff, "symmetric diff"},
+	{'&', exprIntersect, "intersect"},
+	{'-', exprDiff, "diff"},
+}
+
+// exprNode is a node of the evaluation tree. The operators are n-ary, the
+// chains of the same operator are flattened into one node while parsing.
+type exprNode struct {
+	op  exprOp
+	pos int
+	// name of a set for exprName
+	name string
+	// elements of a literal set for exprLiteral
+	elems []interface{}
+	// operands of the operators
+	args []*exprNode
+}
+
+// precedence returns the binding power of the node, operands are higher
+// than all operators.
+func (n *exprNode) precedence() int {
+	if n.op <= exprLiteral {
+		return len(exprOps) + 1
+	}
+	return int(n.op-exprUnion) + 1
+}
+
+func (n *exprNode) symbol() byte {
+	return exprOps[n.op-exprUnion].sym
+}
+
+func (n *exprNode) opName() string {
+	return exprOps[n.op-exprUnion].name
+}
+
+// Expr is a parsed set expression like `A & (B | C) - D`. The operators
+// are, from the lowest precedence to the highest as in Python:
+//
+//	|  union
+//	^  symmetric difference
+//	&  intersection
+//	-  difference
+//
+// Operands are set names, parenthesized expressions and literal sets of
+// ints and quoted strings like `{1, -2, "a"}`. A set name starts with a
+// letter or underscore, followed by letters, digits, underscores, dots
+// and colons.
+type Expr struct {
+	src  string
+	root *exprNode
+}
+
+// parseExpr parses src into an Expr.
+func parseExpr(src string) (*Expr, error) {
+	p := &exprParser{src: src}
+	p.next()
+	root := p.parseLevel(0)
+	if p.err == nil && p.tok.kind != tokEOF {
+		p.fail(p.tok.pos, "unexpected %s", p.tok)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// Eval evaluates the expression with the named sets and returns the
+// result as a new thread unsafe set, the given sets are never modified.
+// It returns an *ExprError if the expression refers to an undefined set,
+// all names are checked before the evaluation, and an *UnhashableError if
+// the operands of a symmetric difference hold unhashable elements.
+//
+// The operands of an intersection are evaluated in order and the
+// evaluation stops once one of them is empty, and so does a difference
+// with an empty base. Use Optimize to reorder the operands by their
+// cardinalities first.
+func (e *Expr) Eval(sets map[string]Set) (Set, error) {
+	// check all names first, since the evaluation may stop before reaching
+	// some of them
+	if err := e.checkNames(e.root, sets); err != nil {
+		return nil, err
+	}
+	ret, err := e.eval(e.root, sets)
+	if err != nil {
+		return nil, err
+	}
+	if e.root.op == exprName {
+		// never return the given set itself
+		ret = UniteAll(ret)
+	}
+	return ret, nil
+}
+
+// checkNames returns an *ExprError for the first undefined set in n.
+func (e *Expr) checkNames(n *exprNode, sets map[string]Set) error {
+	if n.op == exprName {
+		if s, ok := sets[n.name]; !ok || s == nil {
+			return &ExprError{Expr: e.src, Pos: n.pos, Msg: fmt.Sprintf("undefined set %q", n.name)}
+		}
+	}
+	for _, arg := range n.args {
+		if err := e.checkNames(arg, sets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Expr) eval(n *exprNode, sets map[string]Set) (Set, error) {
+	switch n.op {
+	case exprName:
+		s, ok := sets[n.name]
+		if !ok || s == nil {
+			return nil, &ExprError{Expr: e.src, Pos: n.pos, Msg: fmt.Sprintf("undefined set %q", n.name)}
+		}
+		return s, nil
+	case exprLiteral:
+		return newSet(n.elems...), nil
+	}
+
+	args := make([]Set, 0, len(n.args))
+	for i, arg := range n.args {
+		s, err := e.eval(arg, sets)
+		if err != nil {
+			return nil, err
+		}
+		if s.Len() == 0 && (n.op == exprIntersect || n.op == exprDiff && i == 0) {
+			return newSet(), nil
+		}
+		args = append(args, s)
+	}
+	switch n.op {
+	case exprUnion:
+		return UniteAll(args...), nil
+	case exprIntersect:
+		return IntersectAll(args...), nil
+	case exprDiff:
+		return DiffAll(args[0], args[1:]...), nil
+	}
+	ret := UniteAll(args[0])
+	for _, s := range args[1:] {
+		if err := ret.SymmetricDiffWith(s); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// Names returns the sorted names of the sets the expression refers to.
+func (e *Expr) Names() []string {
+	seen := map[string]bool{}
+	var walk func(n *exprNode)
+	walk = func(n *exprNode) {
+		if n.op == exprName {
+			seen[n.name] = true
+		}
+		for _, arg := range n.args {
+			walk(arg)
+		}
+	}
+	walk(e.root)
+	ret := make([]string, 0, len(seen))
+	for name := range seen {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// String returns the expression in canonical form with minimal
+// parentheses.
+func (e *Expr) String() string {
+	var b bytes.Buffer
+	writeExpr(&b, e.root)
+	return b.String()
+}
+
+func writeExpr(b *bytes.Buffer, n *exprNode) {
+	switch n.op {
+	case exprName:
+		b.WriteString(n.name)
+		return
+	case exprLiteral:
+		b.WriteByte('{')
+		for i, elem := range n.elems {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if s, ok := elem.(string); ok {
+				b.WriteString(strconv.Quote(s))
+			} else {
+				fmt.Fprint(b, elem)
+			}
+		}
+		b.WriteByte('}')
+		return
+	}
+	for i, arg := range n.args {
+		if i > 0 {
+			b.WriteByte(' ')
+			b.WriteByte(n.symbol())
+			b.WriteByte(' ')
+		}
+		// difference is not associative, a - (b - c) keeps its parentheses
+		paren := arg.precedence() < n.precedence() ||
+			arg.precedence() == n.precedence() && n.op == exprDiff && i > 0
+		if paren {
+			b.WriteByte('(')
+		}
+		writeExpr(b, arg)
+		if paren {
+			b.WriteByte(')')
+		}
+	}
+}
+
+// Explain returns the evaluation tree of the expression, one node per line
+// and indented by depth. If sets is not nil, each node is annotated with
+// the cardinality of the set, or the estimated one of an operator.
+func (e *Expr) Explain(sets map[string]Set) string {
+	var b bytes.Buffer
+	var walk func(n *exprNode, depth int)
+	walk = func(n *exprNode, depth int) {
+		b.Write(bytes.Repeat([]byte("  "), depth))
+		switch n.op {
+		case exprName, exprLiteral:
+			writeExpr(&b, n)
+		default:
+			b.WriteString(n.opName())
+		}
+		if sets != nil {
+			switch {
+			case n.op == exprName && sets[n.name] == nil:
+				b.WriteString(" (undefined)")
+			case n.op <= exprLiteral:
+				fmt.Fprintf(&b, " (%d)", estimateExpr(n, sets))
+			default:
+				fmt.Fprintf(&b, " (~%d)", estimateExpr(n, sets))
+			}
+		}
+		b.WriteByte('\n')
+		for _, arg := range n.args {
+			walk(arg, depth+1)
+		}
+	}
+	walk(e.root, 0)
+	return b.String()
+}
+
+// estimateExpr returns the upper bound of the cardinality of the result
+// of n. Undefined sets are treated as empty.
+func estimateExpr(n *exprNode, sets map[string]Set) int {
+	switch n.op {
+	case exprName:
+		if s := sets[n.name]; s != nil {
+			return s.Len()
+		}
+		return 0
+	case exprLiteral:
+		return len(n.elems)
+	case exprIntersect:
+		ret := estimateExpr(n.args[0], sets)
+		for _, arg := range n.args[1:] {
+			ret = minInt(ret, estimateExpr(arg, sets))
+		}
+		return ret
+	case exprDiff:
+		return estimateExpr(n.args[0], sets)
+	}
+	ret := 0
+	for _, arg := range n.args {
+		ret += estimateExpr(arg, sets)
+	}
+	return ret
+}
+
+// Optimize returns a new expression with the operands reordered by their
+// estimated cardinalities in the given sets: the operands of an
+// intersection from the smallest to the largest, so that the evaluation
+// stops early on an empty one, and the subtrahends of a difference from
+// the largest to the smallest. The result of Eval is not changed.
+func (e *Expr) Optimize(sets map[string]Set) *Expr {
+	return &Expr{src: e.src, root: optimizeExpr(e.root, sets)}
+}
+
+func optimizeExpr(n *exprNode, sets map[string]Set) *exprNode {
+	if len(n.args) == 0 {
+		return n
+	}
+	ret := *n
+	ret.args = make([]*exprNode, len(n.args))
+	sizes := make(map[*exprNode]int, len(n.args))
+	for i, arg := range n.args {
+		ret.args[i] = optimizeExpr(arg, sets)
+		sizes[ret.args[i]] = estimateExpr(ret.args[i], sets)
+	}
+	switch n.op {
+	case exprIntersect:
+		sort.SliceStable(ret.args, func(i, j int) bool {
+			return sizes[ret.args[i]] < sizes[ret.args[j]]
+		})
+	case exprDiff:
+		rest := ret.args[1:]
+		sort.SliceStable(rest, func(i, j int) bool {
+			return sizes[rest[i]] > sizes[rest[j]]
+		})
+	}
+	return &ret
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokName
+	tokInt
+	tokString
+	tokSymbol
+)
+
+type token struct {
+	kind tokenKind
+	pos  int
+	text string
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokName:
+		return fmt.Sprintf("name %s", t.text)
+	case tokInt:
+		return fmt.Sprintf("int %s", t.text)
+	case tokString:
+		return fmt.Sprintf("string %s", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+type exprParser struct {
+	src string
+	off int
+	tok token
+	err *ExprError
+}
+
+func (p *exprParser) fail(pos int, format string, args ...interface{}) {
+	if p.err == nil {
+		p.err = &ExprError{Expr: p.src, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+	}
+	// stop scanning
+	p.off = len(p.src)
+	p.tok = token{kind: tokEOF, pos: len(p.src)}
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isNamePart(r rune) bool {
+	return isNameStart(r) || r == '.' || r == ':' || unicode.IsDigit(r)
+}
+
+// next scans the next token into p.tok.
+func (p *exprParser) next() {
+	if p.err != nil {
+		return
+	}
+	for p.off < len(p.src) && unicode.IsSpace(rune(p.src[p.off])) {
+		p.off++
+	}
+	start := p.off
+	if start == len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+	r, size := utf8.DecodeRuneInString(p.src[start:])
+	switch {
+	case isNameStart(r):
+		p.off += size
+		for p.off < len(p.src) {
+			r, size := utf8.DecodeRuneInString(p.src[p.off:])
+			if !isNamePart(r) {
+				break
+			}
+			p.off += size
+		}
+		p.tok = token{kind: tokName, pos: start, text: p.src[start:p.off]}
+	case r >= '0' && r <= '9':
+		for p.off < len(p.src) && p.src[p.off] >= '0' && p.src[p.off] <= '9' {
+			p.off++
+		}
+		p.tok = token{kind: tokInt, pos: start, text: p.src[start:p.off]}
+	case r == '"':
+		quoted, err := strconv.QuotedPrefix(p.src[start:])
+		if err != nil {
+			p.fail(start, "unterminated string")
+			return
+		}
+		p.off += len(quoted)
+		p.tok = token{kind: tokString, pos: start, text: quoted}
+	case bytes.ContainsRune([]byte("|^&-(){},"), r):
+		p.off++
+		p.tok = token{kind: tokSymbol, pos: start, text: p.src[start:p.off]}
+	default:
+		p.fail(start, "unexpected character %q", r)
+	}
+}
+
+func (p *exprParser) isSymbol(sym byte) bool {
+	return p.tok.kind == tokSymbol && p.tok.text[0] == sym
+}
+
+// parseLevel parses the operators of the i-th precedence level and the
+// higher ones.
+func (p *exprParser) parseLevel(i int) *exprNode {
+	if i == len(exprOps) {
+		return p.parseOperand()
+	}
+	left := p.parseLevel(i + 1)
+	for p.err == nil && p.isSymbol(exprOps[i].sym) {
+		pos := p.tok.pos
+		p.next()
+		right := p.parseLevel(i + 1)
+		if p.err != nil {
+			return nil
+		}
+		left = joinExpr(exprOps[i].op, pos, left, right)
+	}
+	return left
+}
+
+// joinExpr returns the node of `left op right`, the chains of the same
+// operator are flattened.
+func joinExpr(op exprOp, pos int, left, right *exprNode) *exprNode {
+	n := &exprNode{op: op, pos: pos}
+	if left.op == op {
+		n.args = append(n.args, left.args...)
+	} else {
+		n.args = append(n.args, left)
+	}
+	// all operators but difference are associative
+	if right.op == op && op != exprDiff {
+		n.args = append(n.args, right.args...)
+	} else {
+		n.args = append(n.args, right)
+	}
+	return n
+}
+
+func (p *exprParser) parseOperand() *exprNode {
+	if p.err != nil {
+		return nil
+	}
+	tok := p.tok
+	switch {
+	case tok.kind == tokName:
+		p.next()
+		return &exprNode{op: exprName, pos: tok.pos, name: tok.text}
+	case p.isSymbol('('):
+		p.next()
+		n := p.parseLevel(0)
+		if p.err == nil && !p.isSymbol(')') {
+			p.fail(p.tok.pos, "unexpected %s, expected ')' to close '(' at position %d", p.tok, tok.pos)
+		}
+		p.next()
+		return n
+	case p.isSymbol('{'):
+		return p.parseLiteral()
+	}
+	p.fail(tok.pos, "unexpected %s, expected set name, '(' or '{'", tok)
+	return nil
+}
+
+// parseLiteral parses a literal set of ints and quoted strings.
+func (p *exprParser) parseLiteral() *exprNode {
+	n := &exprNode{op: exprLiteral, pos: p.tok.pos, elems: []interface{}{}}
+	p.next()
+	if p.isSymbol('}') {
+		p.next()
+		return n
+	}
+	for p.err == nil {
+		tok := p.tok
+		neg := false
+		if p.isSymbol('-') {
+			neg = true
+			p.next()
+		}
+		switch {
+		case p.tok.kind == tokInt:
+			text := p.tok.text
+			if neg {
+				text = "-" + text
+			}
+			i, err := strconv.Atoi(text)
+			if err != nil {
+				p.fail(tok.pos, "invalid int %s", text)
+				return nil
+			}
+			n.elems = append(n.elems, i)
+		case p.tok.kind == tokString && !neg:
+			s, _ := strconv.Unquote(p.tok.text)
+			n.elems = append(n.elems, s)
+		default:
+			p.fail(p.tok.pos, "unexpected %s, expected int or string in literal set", p.tok)
+			return nil
+		}
+		p.next()
+		switch {
+		case p.isSymbol(','):
+			p.next()
+		case p.isSymbol('}'):
+			p.next()
+			return n
+		default:
+			p.fail(p.tok.pos, "unexpected %s, expected ',' or '}' in literal set", p.tok)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_ParseExpr(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"name", " A ", "A"},
+		{"precedence", "A | B & C - D ^ E", "A | B & C - D ^ E"},
+		{"diff binds tighter", "(A & B) - C", "(A & B) - C"},
+		{"parentheses", "A & (B | C) - D", "A & (B | C) - D"},
+		{"redundant parentheses", "((A & B)) | (C)", "A & B | C"},
+		{"flatten", "A & (B & C) & D", "A & B & C & D"},
+		{"diff chain", "(A - B) - C", "A - B - C"},
+		{"diff not associative", "A - (B - C)", "A - (B - C)"},
+		{"lower precedence on the left", "(A | B) - C", "(A | B) - C"},
+		{"literal", `{1, -2, "a b", ""} | {}`, `{1, -2, "a b", ""} | {}`},
+		{"names", "users.premium & region:eu & _x1", "users.premium & region:eu & _x1"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := ParseExpr(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseExpr() error = %v", err)
+			}
+			if got := e.String(); got != tt.want {
+				t.Errorf("ParseExpr().String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ParseExpr_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantPos int
+		wantMsg string
+	}{
+		{"empty", "", 0, "unexpected end of expression, expected set name, '(' or '{'"},
+		{"missing operand", "A & ", 4, "unexpected end of expression, expected set name, '(' or '{'"},
+		{"missing operator", "A B", 2, "unexpected name B"},
+		{"unclosed paren", "(A | B", 6, "unexpected end of expression, expected ')' to close '(' at position 0"},
+		{"extra paren", "A | B)", 5, `unexpected ")"`},
+		{"unknown character", "A + B", 2, "unexpected character '+'"},
+		{"unterminated string", `{"a}`, 1, "unterminated string"},
+		{"bad literal", "{1, B}", 4, "unexpected name B, expected int or string in literal set"},
+		{"unclosed literal", "{1 2}", 3, "unexpected int 2, expected ',' or '}' in literal set"},
+		{"int overflow", "{99999999999999999999}", 1, "invalid int 99999999999999999999"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseExpr(tt.expr)
+			var exprErr *ExprError
+			if !errors.As(err, &exprErr) {
+				t.Fatalf("ParseExpr() error = %v, want *ExprError", err)
+			}
+			if exprErr.Pos != tt.wantPos || exprErr.Msg != tt.wantMsg {
+				t.Errorf("ParseExpr() error at %d: %v, want at %d: %v", exprErr.Pos, exprErr.Msg, tt.wantPos, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func Test_Expr_Eval(t *testing.T) {
+	sets := map[string]Set{
+		"A": NewSet(1, 2, 3, 4, 5),
+		"B": NewSafeSet(2, 3, 10),
+		"C": NewBitmapSet(4, 5, 11),
+		"D": NewSortedSet(3, 4),
+		"E": NewSet(),
+	}
+	tests := []struct {
+		name string
+		expr string
+		want Set
+	}{
+		{"name", "A", NewSet(1, 2, 3, 4, 5)},
+		{"union", "B | C", NewSet(2, 3, 4, 5, 10, 11)},
+		{"intersect", "A & B", NewSet(2, 3)},
+		{"diff", "A - B - C", NewSet(1)},
+		{"symmetric diff", "A ^ B ^ C", NewSet(1, 10, 11)},
+		{"mixed", "A & (B | C) - D", NewSet(2, 5)},
+		{"literal", `A & {1, 2, "x"} | {"y"}`, NewSet(1, 2, "y")},
+		{"empty intersect", "E & A & (B | C)", NewSet()},
+		{"empty base", "E - (A | B)", NewSet()},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvalExpr(tt.expr, sets)
+			if err != nil {
+				t.Fatalf("EvalExpr() error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("EvalExpr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	got, _ := EvalExpr("A", sets)
+	got.Add(100)
+	if sets["A"].Contains(100) {
+		t.Errorf("EvalExpr() returns the given set")
+	}
+
+	_, err := EvalExpr("A | (B & Missing)", sets)
+	var exprErr *ExprError
+	if !errors.As(err, &exprErr) || exprErr.Pos != 9 {
+		t.Errorf("EvalExpr() error = %v, want undefined set at position 9", err)
+	}
+
+	sets["Keyed"] = NewKeyedSet(nil, keyedUser{7, []string{"a"}})
+	var unhashable *UnhashableError
+	if _, err := EvalExpr("A ^ Keyed", sets); !errors.As(err, &unhashable) {
+		t.Errorf("EvalExpr() error = %v, want *UnhashableError", err)
+	}
+
+	// names after an empty operand are checked too
+	sets["Empty"] = NewSet()
+	for _, expr := range []string{"Empty & Tpyo", "Empty - Tpyo", "Empty & (A | Tpyo)"} {
+		_, err := EvalExpr(expr, sets)
+		if !errors.As(err, &exprErr) || exprErr.Msg != `undefined set "Tpyo"` {
+			t.Errorf("EvalExpr(%q) error = %v, want undefined set", expr, err)
+		}
+	}
+}
+
+func Test_Expr_Names(t *testing.T) {
+	e, _ := ParseExpr("B & (A | C) - B ^ {1}")
+	if got, want := e.Names(), []string{"A", "B", "C"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
+
+func Test_Expr_Optimize(t *testing.T) {
+	sets := map[string]Set{
+		"Big":   NewSetFromInts(rangeInts(0, 100)),
+		"Mid":   NewSetFromInts(rangeInts(0, 10)),
+		"Small": NewSet(1, 2),
+	}
+	e, _ := ParseExpr("(Big & (Mid | Small) & Small) - Small - Big - {1}")
+	o := e.Optimize(sets)
+	if got, want := o.String(), "(Small & (Mid | Small) & Big) - Big - Small - {1}"; got != want {
+		t.Errorf("Optimize() = %v, want %v", got, want)
+	}
+	if e.String() == o.String() {
+		t.Errorf("Optimize() modifies the expression")
+	}
+	want, _ := e.Eval(sets)
+	if got, _ := o.Eval(sets); !got.Equal(want) {
+		t.Errorf("Optimize().Eval() = %v, want %v", got, want)
+	}
+
+	wantExplain := `diff (~2)
+  intersect (~2)
+    Small (2)
+    union (~12)
+      Mid (10)
+      Small (2)
+    Big (100)
+  Big (100)
+  Small (2)
+  {1} (1)
+`
+	if got := o.Explain(sets); got != wantExplain {
+		t.Errorf("Explain() = \n%v, want \n%v", got, wantExplain)
+	}
+	if got, want := o.Explain(nil), "diff\n  intersect\n    Small\n"; got[:len(want)] != want {
+		t.Errorf("Explain(nil) = \n%v", got)
+	}
+	if got, want := e.Explain(map[string]Set{}), "diff (~0)\n  intersect (~0)\n    Big (undefined)\n"; got[:len(want)] != want {
+		t.Errorf("Explain() with undefined sets = \n%v", got)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"fmt"
+)
+
+// fingerprintSeed is the FNV-1a seed of the low 64 bits of element hashes
+// in a Fingerprint, the high 64 bits are hashed by hashElem.
+const fingerprintSeed = fnvOffset64 ^ goldenRatio64
+
+// Fingerprint is an order-independent 128-bit digest of the contents of a
+// set. Two sets with the same elements have the same Fingerprint, no matter
+// which implementation holds them and in which order the elements are
+// iterated. Fingerprints are comparable, so they can be compared by == and
+// used as map keys. The zero value is the Fingerprint of an empty set.
+//
+// Each element is encoded with a type tag of the bucket it belongs to, so
+// 1, "1" and int64(1) are all different. Equal elements always have the
+// same encoding, like Set comparing them by ==:
+//  1. int is encoded as its 64-bit two's complement value.
+//  2. string is encoded as its bytes.
+//  3. the others are encoded as their type names and values. Floats are
+//     encoded by their bits with -0 as 0, pointers, channels and unsafe
+//     pointers by their addresses instead of what they point to, and
+//     arrays, structs and interfaces by their elements, fields and dynamic
+//     values.
+//
+// The encoding is canonical across processes for ints, strings, bools,
+// floats, the other integer and complex types, and arrays, structs and
+// interfaces of them, but not for pointers, channels or anything
+// containing them. Only Fingerprints of sets with canonically encoded
+// elements are identical across processes. The digest is not
+// cryptographic, it must not be used against adversaries choosing the
+// elements.
+//
+// Fingerprint is not thread safe.
+type Fingerprint struct {
+	n      uint64
+	hi, lo uint64
+}
+
+func newFingerprint(s Set) Fingerprint {
+	f := Fingerprint{}
+	s.Range(func(_ int, elem interface{}) bool {
+		f.toggle(elem, 1)
+		return true
+	})
+	return f
+}
+
+// toggle adds the 128-bit hash of elem to the sums if sign is 1, or
+// subtracts it if sign is -1.
+func (f *Fingerprint) toggle(elem interface{}, sign int) {
+	hi, lo := hashElem(elem), mix64(hashElemSeed(elem, fingerprintSeed))
+	if sign > 0 {
+		f.n++
+		f.lo += lo
+		if f.lo < lo {
+			hi++
+		}
+		f.hi += hi
+		return
+	}
+	f.n--
+	if f.lo < lo {
+		hi++
+	}
+	f.lo -= lo
+	f.hi -= hi
+}
+
+// Add adds the given elements to the set s, and updates the Fingerprint
+// with the ones not in s before, so that it stays the Fingerprint of s.
+// Like Set.Add, if any of elements is invalid, s and the Fingerprint are
+// left unchanged and the error is returned.
+//
+// s must be the fingerprinted set and must not be changed by others in the
+// meantime.
+func (f *Fingerprint) Add(s Set, elems ...interface{}) error {
+	added := make([]interface{}, 0, len(elems))
+	for i, elem := range elems {
+		if s.Contains(elem) {
+			continue
+		}
+		if err := s.Add(elem); err != nil {
+			// roll back the added elements
+			f.Remove(s, added...)
+			if e, ok := err.(*UnhashableError); ok {
+				e.Index = i
+			}
+			return err
+		}
+		f.toggle(elem, 1)
+		added = append(added, elem)
+	}
+	return nil
+}
+
+// Remove removes the given elements from the set s, and updates the
+// Fingerprint with the ones in s before. Like Add, s must be the
+// fingerprinted set.
+func (f *Fingerprint) Remove(s Set, elems ...interface{}) {
+	for _, elem := range elems {
+		if s.Contains(elem) {
+			s.Remove(elem)
+			f.toggle(elem, -1)
+		}
+	}
+}
+
+// Len returns the number of elements in the fingerprinted set.
+func (f Fingerprint) Len() int {
+	return int(f.n)
+}
+
+// Sum128 returns the 128-bit digest as its high and low 64 bits.
+func (f Fingerprint) Sum128() (hi, lo uint64) {
+	// mix64 is a bijection, so sets of the same size collide only if
+	// their sums collide
+	hi = mix64(f.hi ^ mix64(f.n))
+	lo = mix64(f.lo + f.n*goldenRatio64)
+	return hi, lo
+}
+
+// Sum64 returns the 64-bit digest.
+func (f Fingerprint) Sum64() uint64 {
+	hi, lo := f.Sum128()
+	return hi ^ lo
+}
+
+// String returns the 128-bit digest in 32 hexadecimal digits.
+func (f Fingerprint) String() string {
+	hi, lo := f.Sum128()
+	return fmt.Sprintf("%016x%016x", hi, lo)
+}
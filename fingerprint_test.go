@@ -0,0 +1,145 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func Test_Fingerprint(t *testing.T) {
+	want := NewFingerprint(NewSet(1, 2, 3, "a", 1.5))
+	tests := []struct {
+		name string
+		s    Set
+		same bool
+	}{
+		{"same set", NewSet("a", 1.5, 3, 2, 1), true},
+		{"thread safe set", NewSafeSet(3, "a", 2, 1.5, 1), true},
+		{"ordered set", NewOrderedSet(1.5, "a", 3, 2, 1), true},
+		{"sharded set", NewShardedSet(4, 1, 2, 3, "a", 1.5), true},
+		{"missing element", NewSet(1, 2, 3, "a"), false},
+		{"extra element", NewSet(1, 2, 3, "a", 1.5, 4), false},
+		{"int as string", NewSet(1, 2, "3", "a", 1.5), false},
+		{"int as int64", NewSet(1, 2, int64(3), "a", 1.5), false},
+		{"float as int", NewSet(1, 2, 3, "a", 2), false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewFingerprint(tt.s)
+			if (got == want) != tt.same {
+				t.Errorf("NewFingerprint(%v) = %v, want same as %v: %v", tt.s, got, want, tt.same)
+			}
+			if (got.Sum64() == want.Sum64()) != tt.same {
+				t.Errorf("NewFingerprint(%v).Sum64() = %#x, want same as %#x: %v", tt.s, got.Sum64(), want.Sum64(), tt.same)
+			}
+			if got.Len() != tt.s.Len() {
+				t.Errorf("NewFingerprint(%v).Len() = %v, want %v", tt.s, got.Len(), tt.s.Len())
+			}
+		})
+	}
+
+	ints := NewFingerprint(NewSet(1, 2, 3))
+	if got := NewFingerprint(NewBitmapSet(3, 1, 2)); got != ints {
+		t.Errorf("NewFingerprint() of bitmap set = %v, want %v", got, ints)
+	}
+	if got := NewFingerprint(NewSortedSet(3, 1, 2)); got != ints {
+		t.Errorf("NewFingerprint() of sorted set = %v, want %v", got, ints)
+	}
+	if got := NewFingerprint(NewSet()); got != (Fingerprint{}) {
+		t.Errorf("NewFingerprint() of empty set = %v, want the zero value", got)
+	}
+	if NewFingerprint(NewSet()).String() == NewFingerprint(NewSet(0)).String() {
+		t.Errorf("NewFingerprint() of empty set and {0} are the same")
+	}
+
+	// the digest must be stable across processes
+	if got := NewFingerprint(NewSet(42, "goset")).String(); got != "b39b117e356586c5c3dab2a6953cac44" {
+		t.Errorf("NewFingerprint({42, goset}) = %v, want b39b117e356586c5c3dab2a6953cac44", got)
+	}
+}
+
+func Test_Fingerprint_Incremental(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := NewSet()
+	f := NewFingerprint(s)
+	for i := 0; i < 2000; i++ {
+		var elem interface{} = r.Intn(200)
+		if r.Intn(3) == 0 {
+			elem = string(rune('a' + r.Intn(26)))
+		}
+		// elements may be present or absent, like mirroring Set.Add calls
+		if r.Intn(2) == 0 {
+			if err := f.Add(s, elem, elem); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			f.Remove(s, elem)
+		}
+		if i%100 == 0 {
+			if want := NewFingerprint(s); f != want {
+				t.Fatalf("incremental Fingerprint = %v, want %v", f, want)
+			}
+		}
+	}
+	if want := NewFingerprint(s); f != want || f.Len() != s.Len() {
+		t.Fatalf("incremental Fingerprint = %v, want %v", f, want)
+	}
+
+	// invalid elements leave both unchanged
+	before := f
+	err := f.Add(s, 1000, 1001, []int{1})
+	var e *UnhashableError
+	if !errors.As(err, &e) || e.Index != 2 {
+		t.Errorf("Fingerprint.Add() error = %v, want unhashable at index 2", err)
+	}
+	if f != before || s.Contains(1000) {
+		t.Errorf("Fingerprint.Add() with invalid element changes the set or the Fingerprint")
+	}
+
+	f.Remove(s, s.Elements()...)
+	f.Remove(s, 1, "a")
+	if f != (Fingerprint{}) || s.Len() != 0 || f.Len() != 0 {
+		t.Errorf("Fingerprint after removing all elements = %v, want the zero value", f)
+	}
+}
+
+func Test_Fingerprint_Equality(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	if a, b := NewFingerprint(NewSet(0.0)), NewFingerprint(NewSet(negZero)); a != b {
+		t.Errorf("NewFingerprint({0}) = %v, NewFingerprint({-0}) = %v, want the same", a, b)
+	}
+
+	// pointers are fingerprinted by identity, like Set comparing them
+	p := &point{1, 2}
+	s := NewSet(p)
+	f := NewFingerprint(s)
+	p.X = 3
+	if got := NewFingerprint(s); got != f {
+		t.Errorf("NewFingerprint() changes after the pointee is mutated")
+	}
+	f.Remove(s, p)
+	if f != (Fingerprint{}) {
+		t.Errorf("Fingerprint after removing the mutated pointer = %v, want the zero value", f)
+	}
+	if NewFingerprint(NewSet(&point{1, 2})) == NewFingerprint(NewSet(&point{1, 2})) {
+		t.Errorf("NewFingerprint() of different pointers are the same")
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package generic provides a type-safe Set[T] which shares the semantics of
+// the interface{}-based goset.Set.
+package generic
+
+// Set provides a collection of operations for sets whose elements are all of
+// type T.
+//
+// The implementation of Set is base on hash table. T must be comparable, but
+// if T is an interface type, adding an element whose dynamic type is a
+// function, map or slice will cause panic.
+//
+// There are two implementations of Set:
+// 1. default is unsafe based on hash table(map)
+// 2. thread safe based on sync.RWMutex
+//
+// The two kinds of sets can easily convert to the other one. But you must know
+// exactly what you are doing to avoid the concurrent race
+type Set[T comparable] interface {
+	// Add adds all given elements to the set anyway, no matter if it whether already exists.
+	Add(elems ...T)
+
+	// Extend adds all elements in the given set b to this set.
+	Extend(b Set[T])
+
+	// Remove deletes all given elements from the set.
+	Remove(elems ...T)
+
+	// Contains checks whether the given elem is in the set.
+	Contains(elem T) bool
+
+	// ContainsAll checks whether all the given elems are in the set.
+	ContainsAll(elems ...T) bool
+
+	// ContainsAny checks whether any of the given elems is in the set.
+	ContainsAny(elems ...T) bool
+
+	// Copy clones the set.
+	Copy() Set[T]
+
+	// Len returns the size of set. aka Cardinality.
+	Len() int
+
+	// String returns the string representation of the set.
+	String() string
+
+	// Elements returns all elements in this set.
+	Elements() []T
+
+	// Range calls f sequentially for each element present in the set.
+	// If f returns false, range stops the iteration.
+	//
+	// Note: the iteration order is not specified and is not guaranteed
+	// to be the same from one iteration to the next. The index only
+	// means how many elements have been visited in the iteration, it not
+	// specifies the index of an element in the set
+	Range(foreach func(index int, elem T) bool)
+
+	// ---------------------------------------------------------------------
+	// Convert
+
+	// ToThreadUnsafe returns a thread unsafe set.
+	// Carefully use the method.
+	ToThreadUnsafe() Set[T]
+
+	// ToThreadSafe returns a thread safe set.
+	// Carefully use the method.
+	ToThreadSafe() Set[T]
+
+	// ---------------------------------------------------------------------
+	// Compare
+
+	// Equal checks whether this set is equal to the given one.
+	// There are two constraints if set a is equal to set b.
+	// the two set must have the same size and contain the same elements.
+	Equal(b Set[T]) bool
+
+	// IsSubsetOf checks whether this set is the subset of the given set
+	// In other words, all elements in this set are also the elements
+	// of the given set.
+	IsSubsetOf(b Set[T]) bool
+
+	// IsSupersetOf checks whether this set is the superset of the given set
+	// In other words, all elements in the given set are also the elements
+	// of this set.
+	IsSupersetOf(b Set[T]) bool
+
+	// ---------------------------------------------------------------------
+	// Set Operations
+
+	// Diff returns the difference between the set and this given
+	// one, aka Difference Set
+	// math formula: a - b
+	Diff(b Set[T]) Set[T]
+
+	// SymmetricDiff returns the symmetric difference between this set
+	// and the given one. aka Symmetric Difference Set
+	// math formula: (a - b) ∪ (b - a)
+	SymmetricDiff(b Set[T]) Set[T]
+
+	// Unite combines two sets into a new one, aka Union Set
+	// math formula: a ∪ b
+	Unite(b Set[T]) Set[T]
+
+	// Intersect returns the intersection of two set, aka Intersection Set
+	// math formula: a ∩ b
+	Intersect(b Set[T]) Set[T]
+}
+
+// NewSet returns a new Set which contains the
+// given elements
+func NewSet[T comparable](elems ...T) Set[T] {
+	return newSet(elems...)
+}
+
+// NewSetFrom returns a new Set containing
+// all the elements in the slice
+func NewSetFrom[T comparable](elems []T) Set[T] {
+	return newSet(elems...)
+}
+
+// NewSafeSet returns a new thread-safe Set
+// which contains the given elements
+func NewSafeSet[T comparable](elems ...T) Set[T] {
+	return newThreadSafeSet(elems...)
+}
+
+// NewSafeSetFrom returns a new thread-safe Set
+// containing all the elements in the slice
+func NewSafeSetFrom[T comparable](elems []T) Set[T] {
+	return newThreadSafeSet(elems...)
+}
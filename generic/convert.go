@@ -0,0 +1,62 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"fmt"
+
+	"github.com/zoumo/goset"
+)
+
+// ToSet converts the given Set[T] to an interface{}-based goset.Set
+// containing the same elements. The returned set is thread safe if and only
+// if the given one is.
+func ToSet[T comparable](s Set[T]) goset.Set {
+	ret := goset.NewSet()
+	s.Range(func(_ int, elem T) bool {
+		ret.Add(elem) //nolint:errcheck
+		return true
+	})
+	if _, ok := s.(*threadSafeSet[T]); ok {
+		return ret.ToThreadSafe()
+	}
+	return ret
+}
+
+// FromSet converts the given goset.Set to a Set[T]. It returns an error
+// if any element in the given set is not of type T. The returned set is
+// thread safe if and only if the given one is.
+func FromSet[T comparable](s goset.Set) (Set[T], error) {
+	ret := newSet[T]()
+	var err error
+	s.Range(func(_ int, elem interface{}) bool {
+		e, ok := elem.(T)
+		if !ok {
+			err = fmt.Errorf("error convert set element %v of type %T to %T", elem, elem, e)
+			return false
+		}
+		ret.Add(e)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.ToThreadSafe() == s {
+		return ret.ToThreadSafe(), nil
+	}
+	return ret, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/zoumo/goset"
+)
+
+func Test_ToSet(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Set[int]
+		want goset.Set
+		safe bool
+	}{
+		{"unsafe", NewSet(1, 2, 3), goset.NewSet(1, 2, 3), false},
+		{"safe", NewSafeSet(1, 2), goset.NewSet(1, 2), true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToSet(tt.s)
+			if !got.Equal(tt.want) {
+				t.Errorf("ToSet() = %v, want %v", got, tt.want)
+			}
+			if safe := got.ToThreadSafe() == got; safe != tt.safe {
+				t.Errorf("ToSet() thread safe = %v, want %v", safe, tt.safe)
+			}
+		})
+	}
+}
+
+func Test_FromSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       goset.Set
+		want    Set[string]
+		safe    bool
+		wantErr bool
+	}{
+		{"unsafe", goset.NewSet("1", "2"), NewSet("1", "2"), false, false},
+		{"safe", goset.NewSafeSet("1"), NewSet("1"), true, false},
+		{"mixed", goset.NewSet("1", 2), nil, false, true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromSet[string](tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromSet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("FromSet() = %v, want %v", got, tt.want)
+			}
+			if safe := got.ToThreadSafe() == got; safe != tt.safe {
+				t.Errorf("FromSet() thread safe = %v, want %v", safe, tt.safe)
+			}
+		})
+	}
+}
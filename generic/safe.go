@@ -0,0 +1,173 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"reflect"
+	"sync"
+)
+
+type threadSafeSet[T comparable] struct {
+	unsafe set[T]
+	mu     sync.RWMutex
+}
+
+func newThreadSafeSet[T comparable](elems ...T) *threadSafeSet[T] {
+	return &threadSafeSet[T]{
+		unsafe: newSet(elems...),
+	}
+}
+
+// rlockSets holds the read locks of s and b if it is a thread safe set,
+// and returns the function to release them. Locks are acquired in the
+// order of their addresses and each lock is acquired once, so that
+// concurrent operations on the same sets in different orders never
+// deadlock with pending writers.
+func rlockSets[T comparable](s *threadSafeSet[T], b Set[T]) func() {
+	safeb, ok := b.(*threadSafeSet[T])
+	if !ok || safeb == s {
+		s.mu.RLock()
+		return s.mu.RUnlock
+	}
+	first, second := s, safeb
+	if reflect.ValueOf(second).Pointer() < reflect.ValueOf(first).Pointer() {
+		first, second = second, first
+	}
+	first.mu.RLock()
+	second.mu.RLock()
+	return func() {
+		second.mu.RUnlock()
+		first.mu.RUnlock()
+	}
+}
+
+func (s *threadSafeSet[T]) Add(elems ...T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unsafe.Add(elems...)
+}
+
+func (s *threadSafeSet[T]) Extend(b Set[T]) {
+	if b == nil {
+		return
+	}
+	// copy elements out of b first, so that b's lock is never held
+	// together with the write lock of s
+	elems := b.Elements()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unsafe.Add(elems...)
+}
+
+func (s *threadSafeSet[T]) Remove(elems ...T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unsafe.Remove(elems...)
+}
+
+func (s *threadSafeSet[T]) Contains(elem T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.unsafe.Contains(elem)
+}
+
+func (s *threadSafeSet[T]) ContainsAll(elems ...T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.unsafe.ContainsAll(elems...)
+}
+
+func (s *threadSafeSet[T]) ContainsAny(elems ...T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.unsafe.ContainsAny(elems...)
+}
+
+func (s *threadSafeSet[T]) Copy() Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &threadSafeSet[T]{
+		unsafe: s.unsafe.Copy().(set[T]),
+	}
+}
+
+func (s *threadSafeSet[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.unsafe.Len()
+}
+
+func (s *threadSafeSet[T]) String() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.unsafe.String()
+}
+
+func (s *threadSafeSet[T]) Elements() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.unsafe.Elements()
+}
+
+func (s *threadSafeSet[T]) Range(foreach func(index int, elem T) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.unsafe.Range(foreach)
+}
+
+func (s *threadSafeSet[T]) ToThreadUnsafe() Set[T] {
+	return s.unsafe
+}
+
+func (s *threadSafeSet[T]) ToThreadSafe() Set[T] {
+	return s
+}
+
+func (s *threadSafeSet[T]) Equal(b Set[T]) bool {
+	defer rlockSets(s, b)()
+	return s.unsafe.Equal(b)
+}
+
+func (s *threadSafeSet[T]) IsSubsetOf(b Set[T]) bool {
+	defer rlockSets(s, b)()
+	return s.unsafe.IsSubsetOf(b)
+}
+
+func (s *threadSafeSet[T]) IsSupersetOf(b Set[T]) bool {
+	defer rlockSets(s, b)()
+	return s.unsafe.IsSupersetOf(b)
+}
+
+func (s *threadSafeSet[T]) Diff(b Set[T]) Set[T] {
+	defer rlockSets(s, b)()
+	return s.unsafe.Diff(b)
+}
+
+func (s *threadSafeSet[T]) SymmetricDiff(b Set[T]) Set[T] {
+	defer rlockSets(s, b)()
+	return s.unsafe.SymmetricDiff(b)
+}
+
+func (s *threadSafeSet[T]) Unite(b Set[T]) Set[T] {
+	defer rlockSets(s, b)()
+	return s.unsafe.Unite(b)
+}
+
+func (s *threadSafeSet[T]) Intersect(b Set[T]) Set[T] {
+	defer rlockSets(s, b)()
+	return s.unsafe.Intersect(b)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func Test_threadSafeSet_Add_Remove(t *testing.T) {
+	s := NewSafeSet[string]()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Add(strconv.Itoa(i), strconv.Itoa(i+100))
+			s.Remove(strconv.Itoa(i + 100))
+		}(i)
+	}
+	wg.Wait()
+
+	if s.Len() != 100 {
+		t.Errorf("threadSafeSet.Len() = %v, want 100", s.Len())
+	}
+	for i := 0; i < 100; i++ {
+		if !s.Contains(strconv.Itoa(i)) {
+			t.Errorf("threadSafeSet.Add() missing element: %v", i)
+		}
+	}
+}
+
+func Test_threadSafeSet_Operations(t *testing.T) {
+	s := NewSafeSet(1, 2, 3, 4)
+
+	type test struct {
+		b         Set[int]
+		union     Set[int]
+		intersect Set[int]
+	}
+	tests := []test{
+		{NewSafeSet(3, 4, 5), NewSet(1, 2, 3, 4, 5), NewSet(3, 4)},
+		{NewSet(1), NewSet(1, 2, 3, 4), NewSet(1)},
+	}
+	var wg sync.WaitGroup
+	for i := range tests {
+		tt := tests[i]
+		wg.Add(1)
+		go func(tt test) {
+			defer wg.Done()
+			if got := s.Unite(tt.b); !got.Equal(tt.union) {
+				t.Errorf("threadSafeSet.Unite() = %v, want %v", got, tt.union)
+			}
+			if got := s.Intersect(tt.b); !got.Equal(tt.intersect) {
+				t.Errorf("threadSafeSet.Intersect() = %v, want %v", got, tt.intersect)
+			}
+		}(tt)
+	}
+	wg.Wait()
+}
+
+func Test_threadSafeSet_ToThreadUnsafe_And_Safe(t *testing.T) {
+	s := NewSafeSet(1, 2, 3)
+	if _, ok := s.ToThreadUnsafe().(set[int]); !ok {
+		t.Errorf("threadSafeSet.ToThreadUnsafe() = %T, want set[int]", s.ToThreadUnsafe())
+	}
+	if s.ToThreadSafe() != s {
+		t.Errorf("threadSafeSet.ToThreadSafe() returns a different set")
+	}
+	if _, ok := s.Copy().(*threadSafeSet[int]); !ok {
+		t.Errorf("threadSafeSet.Copy() = %T, want *threadSafeSet[int]", s.Copy())
+	}
+}
+
+func Test_threadSafeSet_Deadlock(t *testing.T) {
+	a, b := NewSafeSet(1, 2), NewSafeSet(2, 3)
+	a.Extend(a)
+	if a.Len() != 2 {
+		t.Errorf("Extend() itself = %v", a)
+	}
+
+	// operations on the same sets in different orders, with pending
+	// writers, never deadlock
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			x, y := a, b
+			if g%2 == 0 {
+				x, y = b, a
+			}
+			for i := 0; i < 1000; i++ {
+				x.Extend(y)
+				x.Equal(y)
+				x.Unite(y)
+				x.Add(i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	if !a.Equal(b) {
+		t.Errorf("a = %v, b = %v, want equal sets", a, b)
+	}
+}
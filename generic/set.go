@@ -0,0 +1,217 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type set[T comparable] map[T]struct{}
+
+func newSet[T comparable](elems ...T) set[T] {
+	s := make(set[T], len(elems))
+	s.Add(elems...)
+	return s
+}
+
+func (s set[T]) Add(elems ...T) {
+	for _, elem := range elems {
+		s[elem] = struct{}{}
+	}
+}
+
+func (s set[T]) Extend(b Set[T]) {
+	if b == nil {
+		return
+	}
+	for key := range b.ToThreadUnsafe().(set[T]) {
+		s[key] = struct{}{}
+	}
+}
+
+func (s set[T]) Remove(elems ...T) {
+	for _, elem := range elems {
+		delete(s, elem)
+	}
+}
+
+func (s set[T]) Contains(elem T) bool {
+	_, ok := s[elem]
+	return ok
+}
+
+func (s set[T]) ContainsAll(elems ...T) bool {
+	for _, elem := range elems {
+		if !s.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s set[T]) ContainsAny(elems ...T) bool {
+	for _, elem := range elems {
+		if s.Contains(elem) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s set[T]) Copy() Set[T] {
+	copy := make(set[T], len(s))
+	for key := range s {
+		copy[key] = struct{}{}
+	}
+	return copy
+}
+
+func (s set[T]) Len() int {
+	return len(s)
+}
+
+func (s set[T]) String() string {
+	buf := bytes.Buffer{}
+	buf.WriteString("Set[")
+	s.Range(func(i int, elem T) bool {
+		if i == 0 {
+			buf.WriteString(fmt.Sprintf("%+v", elem))
+		} else {
+			buf.WriteString(fmt.Sprintf(" %+v", elem))
+		}
+		return true
+	})
+	buf.WriteString("]")
+	return buf.String()
+}
+
+func (s set[T]) Elements() []T {
+	ret := make([]T, 0, len(s))
+	for key := range s {
+		ret = append(ret, key)
+	}
+	return ret
+}
+
+func (s set[T]) Range(foreach func(index int, elem T) bool) {
+	i := 0
+	for key := range s {
+		if !foreach(i, key) {
+			break
+		}
+		i++
+	}
+}
+
+func (s set[T]) ToThreadUnsafe() Set[T] {
+	return s
+}
+
+func (s set[T]) ToThreadSafe() Set[T] {
+	return &threadSafeSet[T]{unsafe: s}
+}
+
+func (s set[T]) Equal(b Set[T]) bool {
+	s2 := b.ToThreadUnsafe().(set[T])
+	if len(s) != len(s2) {
+		return false
+	}
+	return s.isSubsetOf(s2)
+}
+
+func (s set[T]) IsSubsetOf(b Set[T]) bool {
+	s2 := b.ToThreadUnsafe().(set[T])
+	if len(s) > len(s2) {
+		return false
+	}
+	return s.isSubsetOf(s2)
+}
+
+func (s set[T]) IsSupersetOf(b Set[T]) bool {
+	s2 := b.ToThreadUnsafe().(set[T])
+	if len(s2) > len(s) {
+		return false
+	}
+	return s2.isSubsetOf(s)
+}
+
+func (s set[T]) isSubsetOf(b set[T]) bool {
+	for key := range s {
+		if !b.Contains(key) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s set[T]) Diff(b Set[T]) Set[T] {
+	s2 := b.ToThreadUnsafe().(set[T])
+	diff := newSet[T]()
+	for key := range s {
+		if !s2.Contains(key) {
+			diff.Add(key)
+		}
+	}
+	return diff
+}
+
+func (s set[T]) SymmetricDiff(b Set[T]) Set[T] {
+	s2 := b.ToThreadUnsafe().(set[T])
+	diff := newSet[T]()
+	for key := range s {
+		if !s2.Contains(key) {
+			diff.Add(key)
+		}
+	}
+	for key := range s2 {
+		if !s.Contains(key) {
+			diff.Add(key)
+		}
+	}
+	return diff
+}
+
+func (s set[T]) Unite(b Set[T]) Set[T] {
+	s2 := b.ToThreadUnsafe().(set[T])
+	union := make(set[T], len(s)+len(s2))
+	for key := range s {
+		union.Add(key)
+	}
+	for key := range s2 {
+		union.Add(key)
+	}
+	return union
+}
+
+func (s set[T]) Intersect(b Set[T]) Set[T] {
+	s2 := b.ToThreadUnsafe().(set[T])
+
+	x, y := s, s2
+	// find the smaller one
+	if len(x) > len(y) {
+		x, y = y, x
+	}
+
+	intersection := newSet[T]()
+	for key := range x {
+		if y.Contains(key) {
+			intersection.Add(key)
+		}
+	}
+	return intersection
+}
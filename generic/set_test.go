@@ -0,0 +1,194 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"sort"
+	"testing"
+)
+
+func Test_set_Add(t *testing.T) {
+	s := newSet[int]()
+	tests := []struct {
+		name  string
+		elems []int
+		want  int
+	}{
+		{"add ints", []int{1, 2, 3}, 3},
+		{"add dup ints", []int{1, 2, 3}, 3},
+		{"add more ints", []int{3, 4}, 4},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			s.Add(tt.elems...)
+			if !s.ContainsAll(tt.elems...) || s.Len() != tt.want {
+				t.Errorf("set.Add() = %v, want len %v", s, tt.want)
+			}
+		})
+	}
+}
+
+func Test_set_Remove(t *testing.T) {
+	s := newSet("1", "2", "3")
+	tests := []struct {
+		name string
+		elem string
+	}{
+		{"remove", "1"},
+		{"remove missing", "4"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			s.Remove(tt.elem)
+			if s.Contains(tt.elem) {
+				t.Error("set.Remove() element is not removed")
+			}
+		})
+	}
+}
+
+func Test_set_Contains(t *testing.T) {
+	s := newSet(1, 2, 3)
+	tests := []struct {
+		name  string
+		elems []int
+		all   bool
+		any   bool
+	}{
+		{"", []int{1, 2}, true, true},
+		{"", []int{1, 4}, false, true},
+		{"", []int{4, 5}, false, false},
+		{"", []int{}, true, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.ContainsAll(tt.elems...); got != tt.all {
+				t.Errorf("set.ContainsAll() = %v, want %v", got, tt.all)
+			}
+			if got := s.ContainsAny(tt.elems...); got != tt.any {
+				t.Errorf("set.ContainsAny() = %v, want %v", got, tt.any)
+			}
+		})
+	}
+}
+
+func Test_set_Compare(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        Set[int]
+		b        Set[int]
+		equal    bool
+		subset   bool
+		superset bool
+	}{
+		{"", NewSet(1, 2, 3), NewSet(1, 2, 3), true, true, true},
+		{"", NewSet(1, 2), NewSet(1, 2, 3), false, true, false},
+		{"", NewSet(1, 2, 3), NewSafeSet(1, 2), false, false, true},
+		{"", NewSet(1, 4), NewSet(1, 2, 3), false, false, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.equal {
+				t.Errorf("set.Equal() = %v, want %v", got, tt.equal)
+			}
+			if got := tt.a.IsSubsetOf(tt.b); got != tt.subset {
+				t.Errorf("set.IsSubsetOf() = %v, want %v", got, tt.subset)
+			}
+			if got := tt.a.IsSupersetOf(tt.b); got != tt.superset {
+				t.Errorf("set.IsSupersetOf() = %v, want %v", got, tt.superset)
+			}
+		})
+	}
+}
+
+func Test_set_Operations(t *testing.T) {
+	tests := []struct {
+		name      string
+		a         Set[string]
+		b         Set[string]
+		diff      Set[string]
+		symmetric Set[string]
+		union     Set[string]
+		intersect Set[string]
+	}{
+		{
+			"",
+			NewSet("1", "2", "3"),
+			NewSet("2", "3", "4"),
+			NewSet("1"),
+			NewSet("1", "4"),
+			NewSet("1", "2", "3", "4"),
+			NewSet("2", "3"),
+		},
+		{
+			"",
+			NewSet("1", "2"),
+			NewSafeSet[string](),
+			NewSet("1", "2"),
+			NewSet("1", "2"),
+			NewSet("1", "2"),
+			NewSet[string](),
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Diff(tt.b); !got.Equal(tt.diff) {
+				t.Errorf("set.Diff() = %v, want %v", got, tt.diff)
+			}
+			if got := tt.a.SymmetricDiff(tt.b); !got.Equal(tt.symmetric) {
+				t.Errorf("set.SymmetricDiff() = %v, want %v", got, tt.symmetric)
+			}
+			if got := tt.a.Unite(tt.b); !got.Equal(tt.union) {
+				t.Errorf("set.Unite() = %v, want %v", got, tt.union)
+			}
+			if got := tt.a.Intersect(tt.b); !got.Equal(tt.intersect) {
+				t.Errorf("set.Intersect() = %v, want %v", got, tt.intersect)
+			}
+		})
+	}
+}
+
+func Test_set_Copy_And_Extend(t *testing.T) {
+	a := NewSetFrom([]int{1, 2})
+	b := a.Copy()
+	b.Extend(NewSet(3))
+	if a.Contains(3) {
+		t.Errorf("set.Copy() shares storage with the origin set")
+	}
+	got := b.Elements()
+	sort.Ints(got)
+	if len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Errorf("set.Extend() = %v, want [1 2 3]", got)
+	}
+}
+
+func Test_set_Range(t *testing.T) {
+	s := NewSet(1, 2, 3, 4)
+	seen := 0
+	s.Range(func(index int, elem int) bool {
+		seen++
+		return index < 1
+	})
+	if seen != 2 {
+		t.Errorf("Range visited %v elements, want 2", seen)
+	}
+}
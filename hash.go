@@ -0,0 +1,163 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"math"
+	"reflect"
+)
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+
+	goldenRatio64 = 0x9e3779b97f4a7c15
+)
+
+// type tags of elements used in hashing, elements in different typed sets
+// never have the same encoding.
+const (
+	tagInt byte = iota + 1
+	tagString
+	tagAny
+)
+
+// hashElem returns a 64-bit hash of elem by FNV-1a. The hash of int and
+// string elements is stable across processes, the others are hashed by
+// their types and values, see fnvValue.
+func hashElem(elem interface{}) uint64 {
+	return hashElemSeed(elem, fnvOffset64)
+}
+
+// hashElemSeed is like hashElem, but starts FNV-1a from the given seed
+// instead of the offset basis.
+func hashElemSeed(elem interface{}, seed uint64) uint64 {
+	h := seed
+	switch e := elem.(type) {
+	case int:
+		h = fnvByte(h, tagInt)
+		h = fnvUint64(h, uint64(e))
+	case string:
+		h = fnvByte(h, tagString)
+		h = fnvString(h, e)
+	default:
+		h = fnvByte(h, tagAny)
+		h = fnvValue(h, reflect.ValueOf(elem))
+	}
+	return mix64(h)
+}
+
+// hashElemPair returns two hashes of elem for double hashing, the i-th
+// derived hash is a + i*b. b is always odd so that it never degenerates.
+func hashElemPair(elem interface{}) (a, b uint64) {
+	a = hashElem(elem)
+	return a, mix64(a+goldenRatio64) | 1
+}
+
+func fnvByte(h uint64, b byte) uint64 {
+	h ^= uint64(b)
+	return h * fnvPrime64
+}
+
+func fnvUint64(h uint64, v uint64) uint64 {
+	for i := 0; i < 8; i++ {
+		h = fnvByte(h, byte(v>>(8*i)))
+	}
+	return h
+}
+
+func fnvString(h uint64, s string) uint64 {
+	for i := 0; i < len(s); i++ {
+		h = fnvByte(h, s[i])
+	}
+	return h
+}
+
+// mix64 is the finalizer of MurmurHash3, it spreads the entropy of h to
+// all bits.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// fnvValue hashes the type and the value of v consistently with ==, so
+// that equal elements always have the same hash:
+//  1. floats are hashed by their bits, with -0 as 0 and all NaNs the same.
+//  2. pointers, channels, funcs and unsafe pointers are hashed by their
+//     addresses instead of what they point to.
+//  3. arrays, structs and interfaces are hashed by their elements, fields
+//     and dynamic values.
+//
+// The hash is stable across processes unless v contains addresses.
+func fnvValue(h uint64, v reflect.Value) uint64 {
+	if !v.IsValid() {
+		// nil interface
+		return fnvByte(h, 0)
+	}
+	h = fnvString(h, v.Type().String())
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return fnvByte(h, 1)
+		}
+		return fnvByte(h, 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fnvUint64(h, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return fnvUint64(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return fnvFloat(h, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return fnvFloat(fnvFloat(h, real(c)), imag(c))
+	case reflect.String:
+		h = fnvUint64(h, uint64(v.Len()))
+		return fnvString(h, v.String())
+	case reflect.Ptr, reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Map, reflect.Slice:
+		// maps and slices are unhashable, they never reach here in sets
+		return fnvUint64(h, uint64(v.Pointer()))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			h = fnvValue(h, v.Index(i))
+		}
+		return h
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			h = fnvValue(h, v.Field(i))
+		}
+		return h
+	case reflect.Interface:
+		return fnvValue(h, v.Elem())
+	}
+	return h
+}
+
+// fnvFloat hashes f by its bits, -0 is hashed as 0 and all NaNs are hashed
+// the same.
+func fnvFloat(h uint64, f float64) uint64 {
+	switch {
+	case f == 0:
+		f = 0
+	case math.IsNaN(f):
+		f = math.NaN()
+	}
+	return fnvUint64(h, math.Float64bits(f))
+}
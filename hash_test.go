@@ -0,0 +1,83 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"math"
+	"testing"
+)
+
+type point struct {
+	X, Y float64
+}
+
+type wrapper struct {
+	v interface{}
+}
+
+func Test_hashElem(t *testing.T) {
+	p := &point{1, 2}
+	tests := []struct {
+		name string
+		a    interface{}
+		b    interface{}
+		same bool
+	}{
+		{"same int", 1, 1, true},
+		{"same string", "a", "a", true},
+		{"same any", 1.5, 1.5, true},
+		{"int and string", 1, "1", false},
+		{"int and float", 1, 1.0, false},
+		{"int and int64", 1, int64(1), false},
+		{"different ints", 1, 2, false},
+		{"zero and negative zero", 0.0, math.Copysign(0, -1), true},
+		{"NaNs", math.NaN(), -math.NaN(), true},
+		{"float32 zeros", float32(0), float32(math.Copysign(0, -1)), true},
+		{"complex zeros", complex(0, 0), complex(math.Copysign(0, -1), 0), true},
+		{"same pointer", p, p, true},
+		{"pointers to equal values", p, &point{1, 2}, false},
+		{"structs with zeros", point{X: 0}, point{X: math.Copysign(0, -1)}, true},
+		{"structs with interfaces", wrapper{1}, wrapper{1}, true},
+		{"structs with different interfaces", wrapper{1}, wrapper{int64(1)}, false},
+		{"structs with nil interfaces", wrapper{}, wrapper{nil}, true},
+		{"arrays", [2]string{"a", "bc"}, [2]string{"a", "bc"}, true},
+		{"arrays with moved bytes", [2]string{"a", "bc"}, [2]string{"ab", "c"}, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hashElem(tt.a) == hashElem(tt.b); got != tt.same {
+				t.Errorf("hashElem(%v) == hashElem(%v) is %v, want %v", tt.a, tt.b, got, tt.same)
+			}
+		})
+	}
+
+	// pointers are hashed by their addresses like ==
+	before := hashElem(p)
+	p.X = 3
+	if hashElem(p) != before {
+		t.Errorf("hashElem() of a pointer changes after the pointee is mutated")
+	}
+
+	// the hash must be stable across processes
+	if got := hashElem("goset"); got != 0xe737b95c0162d3be {
+		t.Errorf("hashElem(\"goset\") = %#x, want 0xe737b95c0162d3be", got)
+	}
+	if got := hashElem(42); got != 0x640467e21fb54bb {
+		t.Errorf("hashElem(42) = %#x, want 0x640467e21fb54bb", got)
+	}
+}
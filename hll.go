@@ -0,0 +1,228 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+const (
+	hllVersion      byte = 1
+	minHLLPrecision      = 4
+	maxHLLPrecision      = 18
+)
+
+// HyperLogLog estimates the number of distinct elements with a fixed small
+// memory of 2^precision one-byte registers.
+//
+// The relative standard error of Estimate is about 1.04/sqrt(2^precision),
+// e.g. 1.6% for precision 12 with 4KB registers, see RelativeError. Sketches
+// with the same precision can be merged to estimate the cardinality of the
+// union, and the intersection is estimated by inclusion–exclusion, whose
+// absolute error is about that of the union, so it is inaccurate when the
+// intersection is much smaller than the union.
+//
+// Elements are hashed like BloomFilter, ints and strings are hashed stably
+// across processes. HyperLogLog is not thread safe.
+type HyperLogLog struct {
+	p   uint8
+	reg []uint8
+}
+
+func newHyperLogLog(precision int) *HyperLogLog {
+	if precision < minHLLPrecision || precision > maxHLLPrecision {
+		panic(fmt.Sprintf("error create hyperloglog with precision %d, it must be in [%d, %d]", precision, minHLLPrecision, maxHLLPrecision))
+	}
+	return &HyperLogLog{
+		p:   uint8(precision),
+		reg: make([]uint8, 1<<precision),
+	}
+}
+
+// Add adds all given elements to the sketch.
+func (h *HyperLogLog) Add(elems ...interface{}) {
+	for _, elem := range elems {
+		x := hashElem(elem)
+		i := x >> (64 - h.p)
+		rho := uint8(bits.LeadingZeros64(x<<h.p|1<<(h.p-1)) + 1)
+		if rho > h.reg[i] {
+			h.reg[i] = rho
+		}
+	}
+}
+
+// AddSet adds all elements in the given Set to the sketch.
+func (h *HyperLogLog) AddSet(s Set) {
+	s.Range(func(_ int, elem interface{}) bool {
+		h.Add(elem)
+		return true
+	})
+}
+
+// AddStream adds elements received from the given channel to the sketch
+// until it is closed, and returns the number of received elements.
+func (h *HyperLogLog) AddStream(elems <-chan interface{}) int {
+	n := 0
+	for elem := range elems {
+		h.Add(elem)
+		n++
+	}
+	return n
+}
+
+// Precision returns the precision of the sketch.
+func (h *HyperLogLog) Precision() int {
+	return int(h.p)
+}
+
+// RelativeError returns the relative standard error of the estimates,
+// which is 1.04/sqrt(2^precision).
+func (h *HyperLogLog) RelativeError() float64 {
+	return 1.04 / math.Sqrt(float64(len(h.reg)))
+}
+
+// Estimate returns the estimated number of distinct elements added.
+func (h *HyperLogLog) Estimate() int {
+	return int(math.Round(h.estimate()))
+}
+
+func (h *HyperLogLog) estimate() float64 {
+	m := float64(len(h.reg))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.reg {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	e := hllAlpha(len(h.reg)) * m * m / sum
+	// use linear counting for small cardinalities
+	if e <= 2.5*m && zeros > 0 {
+		return m * math.Log(m/float64(zeros))
+	}
+	return e
+}
+
+func hllAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// Copy clones the sketch.
+func (h *HyperLogLog) Copy() *HyperLogLog {
+	reg := make([]uint8, len(h.reg))
+	copy(reg, h.reg)
+	return &HyperLogLog{p: h.p, reg: reg}
+}
+
+// Merge merges the given sketch into this one, after that this sketch
+// estimates the union of the elements added to both. The two sketches
+// must have the same precision.
+func (h *HyperLogLog) Merge(b *HyperLogLog) error {
+	if h.p != b.p {
+		return fmt.Errorf("error merge hyperloglogs with different precisions: %d and %d", h.p, b.p)
+	}
+	for i, r := range b.reg {
+		if r > h.reg[i] {
+			h.reg[i] = r
+		}
+	}
+	return nil
+}
+
+// Unite returns a new sketch of the union of elements added to the two
+// sketches.
+func (h *HyperLogLog) Unite(b *HyperLogLog) (*HyperLogLog, error) {
+	ret := h.Copy()
+	if err := ret.Merge(b); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// UnionEstimate estimates the number of distinct elements added to
+// either sketch.
+func (h *HyperLogLog) UnionEstimate(b *HyperLogLog) (int, error) {
+	u, err := h.Unite(b)
+	if err != nil {
+		return 0, err
+	}
+	return u.Estimate(), nil
+}
+
+// IntersectEstimate estimates the number of distinct elements added to
+// both sketches by inclusion–exclusion: |a ∩ b| = |a| + |b| - |a ∪ b|.
+func (h *HyperLogLog) IntersectEstimate(b *HyperLogLog) (int, error) {
+	u, err := h.Unite(b)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Round(h.intersect(b, u.estimate()))), nil
+}
+
+func (h *HyperLogLog) intersect(b *HyperLogLog, union float64) float64 {
+	return math.Max(h.estimate()+b.estimate()-union, 0)
+}
+
+// Jaccard estimates the Jaccard index of the elements added to the two
+// sketches, which is |a ∩ b| / |a ∪ b|. It returns 0 if both are empty.
+func (h *HyperLogLog) Jaccard(b *HyperLogLog) (float64, error) {
+	u, err := h.Unite(b)
+	if err != nil {
+		return 0, err
+	}
+	union := u.estimate()
+	if union == 0 {
+		return 0, nil
+	}
+	return math.Min(h.intersect(b, union)/union, 1), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 2+len(h.reg))
+	data[0] = hllVersion
+	data[1] = h.p
+	copy(data[2:], h.reg)
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != hllVersion {
+		return errors.New("error decode hyperloglog: invalid header")
+	}
+	p := data[1]
+	if p < minHLLPrecision || p > maxHLLPrecision || len(data)-2 != 1<<p {
+		return errors.New("error decode hyperloglog: invalid size")
+	}
+	reg := make([]uint8, 1<<p)
+	copy(reg, data[2:])
+	*h = HyperLogLog{p: p, reg: reg}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"math"
+	"testing"
+)
+
+func within(got, want int, rel float64) bool {
+	return math.Abs(float64(got-want)) <= rel*float64(want)
+}
+
+func Test_HyperLogLog_Estimate(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+	}{
+		{"empty", 0},
+		{"small", 100},
+		{"medium", 10000},
+		{"large", 200000},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHyperLogLog(12)
+			for i := 0; i < tt.n; i++ {
+				h.Add(i, i) // duplicates are not counted
+			}
+			// 3 standard errors
+			if got := h.Estimate(); !within(got, tt.n, 3*h.RelativeError()) {
+				t.Errorf("HyperLogLog.Estimate() = %v, want about %v", got, tt.n)
+			}
+		})
+	}
+}
+
+func Test_HyperLogLog_SetOperations(t *testing.T) {
+	a := NewHyperLogLogFrom(NewSetFromInts(rangeInts(0, 30000)), 14)
+	b := NewHyperLogLog(14)
+	ch := make(chan interface{})
+	go func() {
+		defer close(ch)
+		for _, i := range rangeInts(20000, 50000) {
+			ch <- i
+		}
+	}()
+	if n := b.AddStream(ch); n != 30000 {
+		t.Fatalf("HyperLogLog.AddStream() = %v, want 30000", n)
+	}
+
+	union, err := a.UnionEstimate(b)
+	if err != nil || !within(union, 50000, 0.03) {
+		t.Errorf("HyperLogLog.UnionEstimate() = %v, %v, want about 50000", union, err)
+	}
+	inter, err := a.IntersectEstimate(b)
+	if err != nil || !within(inter, 10000, 0.15) {
+		t.Errorf("HyperLogLog.IntersectEstimate() = %v, %v, want about 10000", inter, err)
+	}
+	j, err := a.Jaccard(b)
+	if err != nil || math.Abs(j-0.2) > 0.03 {
+		t.Errorf("HyperLogLog.Jaccard() = %v, %v, want about 0.2", j, err)
+	}
+	if j, _ := NewHyperLogLog(14).Jaccard(NewHyperLogLog(14)); j != 0 {
+		t.Errorf("HyperLogLog.Jaccard() of empty sketches = %v, want 0", j)
+	}
+
+	if err := a.Merge(b); err != nil || a.Estimate() != union {
+		t.Errorf("HyperLogLog.Merge() = %v, estimate %v, want %v", err, a.Estimate(), union)
+	}
+	if err := a.Merge(NewHyperLogLog(10)); err == nil {
+		t.Errorf("HyperLogLog.Merge() with different precisions should return error")
+	}
+}
+
+func Test_HyperLogLog_Binary(t *testing.T) {
+	h := NewHyperLogLogFrom(NewSet(1, "a", 2.5), 6)
+	data, err := h.MarshalBinary()
+	if err != nil || len(data) != 2+64 {
+		t.Fatalf("HyperLogLog.MarshalBinary() = %d bytes, %v", len(data), err)
+	}
+	got := &HyperLogLog{}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("HyperLogLog.UnmarshalBinary() error = %v", err)
+	}
+	if got.Precision() != 6 || got.Estimate() != h.Estimate() {
+		t.Errorf("HyperLogLog.UnmarshalBinary() = %v, want %v", got.Estimate(), h.Estimate())
+	}
+	for _, bad := range [][]byte{nil, {hllVersion, 3}, data[:10], {2, 6}} {
+		if err := got.UnmarshalBinary(bad); err == nil {
+			t.Errorf("HyperLogLog.UnmarshalBinary(%v) should return error", bad)
+		}
+	}
+}
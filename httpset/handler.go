@@ -0,0 +1,446 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httpset provides an http.Handler to inspect and edit the named
+// sets of a goset.Registry at runtime with JSON requests.
+//
+// The routes are relative to the root of the handler, use http.StripPrefix
+// to mount it on a sub path:
+//
+//	GET    /sets                          list the names and cardinalities
+//	GET    /sets/{name}                   get the elements of the set
+//	PUT    /sets/{name}                   replace the elements of the set
+//	DELETE /sets/{name}                   delete the set
+//	POST   /sets/{name}/add               add the elements to the set
+//	POST   /sets/{name}/remove            remove the elements from the set
+//	GET    /sets/{name}/contains?elem=e   check whether e is in the set
+//	GET    /sets/{name}/len               get the cardinality of the set
+//	GET    /ops/{op}?set=a&set=b          union, intersect, diff or symdiff
+//	GET    /eval?expr=a%20%26%20b         evaluate a set expression
+//
+// Request bodies are JSON arrays of elements, integral numbers are decoded
+// as ints. Elements are encoded like the MarshalJSON of sets, which is a
+// plain array if all elements are ints or strings. The elem query parameter
+// is decoded as a JSON value if possible, otherwise as a string, so that
+// elem=1 is the int 1 and elem="1" is the string "1". Missing sets are
+// treated as empty ones by contains, ops and eval.
+//
+// Like Redis, a set is deleted once it is empty. Responses of a set carry
+// its version in ETag, and the mutations of a set accept If-Match, or
+// If-None-Match: * to create a set only if it does not exist, for
+// optimistic concurrency control. A failed precondition is answered with
+// 412 Precondition Failed. GET requests accept If-None-Match and are
+// answered with 304 Not Modified if the set is not changed.
+package httpset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/zoumo/goset"
+)
+
+// maxBodyBytes is the maximum size of request bodies.
+const maxBodyBytes = 8 << 20
+
+var errPreconditionFailed = errors.New("precondition failed")
+
+// Handler serves the sets of a Registry over HTTP.
+type Handler struct {
+	registry *goset.Registry
+}
+
+// NewHandler returns a new Handler of the given registry.
+func NewHandler(registry *goset.Registry) *Handler {
+	return &Handler{registry: registry}
+}
+
+// setInfo is the JSON response of a set.
+type setInfo struct {
+	Name     string          `json:"name,omitempty"`
+	Len      int             `json:"len"`
+	Elements json.RawMessage `json:"elements,omitempty"`
+}
+
+type errorResponse struct {
+	Error    string `json:"error"`
+	Position *int   `json:"position,omitempty"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "sets":
+		if allowMethods(w, r, http.MethodGet) {
+			h.list(w)
+		}
+	case len(parts) == 2 && parts[0] == "sets" && parts[1] != "":
+		h.serveSet(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "sets" && parts[1] != "":
+		h.serveSetAction(w, r, parts[1], parts[2])
+	case len(parts) == 2 && parts[0] == "ops":
+		if allowMethods(w, r, http.MethodGet) {
+			h.operate(w, r, parts[1])
+		}
+	case len(parts) == 1 && parts[0] == "eval":
+		if allowMethods(w, r, http.MethodGet) {
+			h.eval(w, r)
+		}
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no route for %s", r.URL.Path))
+	}
+}
+
+func (h *Handler) serveSet(w http.ResponseWriter, r *http.Request, name string) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPut, http.MethodDelete) {
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		h.get(w, r, name)
+	case http.MethodPut:
+		h.put(w, r, name)
+	case http.MethodDelete:
+		h.delete(w, r, name)
+	}
+}
+
+func (h *Handler) serveSetAction(w http.ResponseWriter, r *http.Request, name, action string) {
+	switch action {
+	case "add", "remove":
+		if allowMethods(w, r, http.MethodPost) {
+			h.update(w, r, name, action == "add")
+		}
+	case "contains":
+		if allowMethods(w, r, http.MethodGet) {
+			h.contains(w, r, name)
+		}
+	case "len":
+		if allowMethods(w, r, http.MethodGet) {
+			h.len(w, r, name)
+		}
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no route for %s", r.URL.Path))
+	}
+}
+
+func (h *Handler) list(w http.ResponseWriter) {
+	names := h.registry.Names()
+	sets := h.registry.Sets(names...)
+	ret := make([]setInfo, len(names))
+	for i, name := range names {
+		ret[i] = setInfo{Name: name, Len: sets[i].Len()}
+	}
+	writeJSON(w, http.StatusOK, ret)
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request, name string) {
+	s, version, ok := h.registry.Snapshot(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("set %q not found", name))
+		return
+	}
+	w.Header().Set("ETag", etag(version))
+	if matchETag(r.Header.Get("If-None-Match"), version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeSet(w, name, s)
+}
+
+func (h *Handler) put(w http.ResponseWriter, r *http.Request, name string) {
+	elems, ok := readElements(w, r)
+	if !ok {
+		return
+	}
+	var info setInfo
+	version, err := h.registry.Update(name, func(_ goset.Set, version uint64) (goset.Set, error) {
+		if err := checkPreconditions(r, version); err != nil {
+			return nil, err
+		}
+		// elems is shared with the registry once stored, encode it while
+		// the registry is still locked
+		var err error
+		info, err = newSetInfo(name, elems)
+		return elems, err
+	})
+	if err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+	if version == 0 {
+		// the empty set is deleted
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("ETag", etag(version))
+	writeJSON(w, http.StatusOK, info)
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, name string) {
+	found := true
+	_, err := h.registry.Update(name, func(s goset.Set, version uint64) (goset.Set, error) {
+		if version == 0 {
+			found = false
+			return nil, nil
+		}
+		if err := checkPreconditions(r, version); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	switch {
+	case err != nil:
+		writeUpdateError(w, err)
+	case !found:
+		writeError(w, http.StatusNotFound, fmt.Errorf("set %q not found", name))
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// update adds or removes the elements in the request body.
+func (h *Handler) update(w http.ResponseWriter, r *http.Request, name string, add bool) {
+	elems, ok := readElements(w, r)
+	if !ok {
+		return
+	}
+	var changed, length int
+	version, err := h.registry.Update(name, func(s goset.Set, version uint64) (goset.Set, error) {
+		if err := checkPreconditions(r, version); err != nil {
+			return nil, err
+		}
+		before := s.Len()
+		if add {
+			// the set may not hold all elements, such as a bitmap set
+			if err := s.UniteWith(elems); err != nil {
+				return nil, err
+			}
+			changed = s.Len() - before
+		} else {
+			s.DiffWith(elems)
+			changed = before - s.Len()
+		}
+		length = s.Len()
+		return s, nil
+	})
+	if err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+	ret := map[string]int{"len": length}
+	if add {
+		ret["added"] = changed
+	} else {
+		ret["removed"] = changed
+	}
+	if version != 0 {
+		w.Header().Set("ETag", etag(version))
+	}
+	writeJSON(w, http.StatusOK, ret)
+}
+
+func (h *Handler) contains(w http.ResponseWriter, r *http.Request, name string) {
+	values, ok := r.URL.Query()["elem"]
+	if !ok || len(values) != 1 {
+		writeError(w, http.StatusBadRequest, errors.New("exactly one elem parameter is required"))
+		return
+	}
+	s := h.registry.Sets(name)[0]
+	writeJSON(w, http.StatusOK, map[string]bool{"contains": s.Contains(parseElement(values[0]))})
+}
+
+func (h *Handler) len(w http.ResponseWriter, r *http.Request, name string) {
+	s, version, ok := h.registry.Snapshot(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("set %q not found", name))
+		return
+	}
+	w.Header().Set("ETag", etag(version))
+	if matchETag(r.Header.Get("If-None-Match"), version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, setInfo{Len: s.Len()})
+}
+
+func (h *Handler) operate(w http.ResponseWriter, r *http.Request, op string) {
+	names := r.URL.Query()["set"]
+	if len(names) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("at least one set parameter is required"))
+		return
+	}
+	sets := h.registry.Sets(names...)
+	var ret goset.Set
+	switch op {
+	case "union":
+		ret = goset.UniteAll(sets...)
+	case "intersect":
+		ret = goset.IntersectAll(sets...)
+	case "diff":
+		ret = goset.DiffAll(sets[0], sets[1:]...)
+	case "symdiff":
+		ret = goset.UniteAll(sets[0])
+		for _, s := range sets[1:] {
+			if err := ret.SymmetricDiffWith(s); err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown operation %q", op))
+		return
+	}
+	writeSet(w, "", ret)
+}
+
+func (h *Handler) eval(w http.ResponseWriter, r *http.Request) {
+	expr, err := goset.ParseExpr(r.URL.Query().Get("expr"))
+	if err != nil {
+		writeExprError(w, err)
+		return
+	}
+	names := expr.Names()
+	sets := make(map[string]goset.Set, len(names))
+	for i, s := range h.registry.Sets(names...) {
+		sets[names[i]] = s
+	}
+	ret, err := expr.Eval(sets)
+	if err != nil {
+		writeExprError(w, err)
+		return
+	}
+	writeSet(w, "", ret)
+}
+
+// parseElement decodes the element as a JSON value, or returns it as a
+// string if it is not valid JSON.
+func parseElement(value string) interface{} {
+	s := goset.NewSet()
+	if err := json.Unmarshal([]byte("["+value+"]"), s); err != nil || s.Len() != 1 {
+		return value
+	}
+	return s.Elements()[0]
+}
+
+// readElements decodes the request body into a set, it writes the error
+// response and returns false on failure.
+func readElements(w http.ResponseWriter, r *http.Request) (goset.Set, bool) {
+	s := goset.NewSet()
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %v", err))
+		return nil, false
+	}
+	if err := json.Unmarshal(raw, s); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid elements: %v", err))
+		return nil, false
+	}
+	return s, true
+}
+
+func etag(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// matchETag reports whether the If-Match or If-None-Match header matches
+// the version of an existing set.
+func matchETag(header string, version uint64) bool {
+	if version == 0 {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPreconditions checks If-Match and If-None-Match of mutations
+// against the version of the set, which is zero if it does not exist.
+func checkPreconditions(r *http.Request, version uint64) error {
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && !matchETag(ifMatch, version) {
+		return fmt.Errorf("%w: the set is at version %s", errPreconditionFailed, etag(version))
+	}
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && matchETag(ifNoneMatch, version) {
+		return fmt.Errorf("%w: the set is at version %s", errPreconditionFailed, etag(version))
+	}
+	return nil
+}
+
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	return false
+}
+
+func newSetInfo(name string, s goset.Set) (setInfo, error) {
+	elems, err := json.Marshal(s)
+	if err != nil {
+		return setInfo{}, err
+	}
+	return setInfo{Name: name, Len: s.Len(), Elements: elems}, nil
+}
+
+func writeSet(w http.ResponseWriter, name string, s goset.Set) {
+	info, err := newSetInfo(name, s)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+// writeUpdateError writes the error of Registry.Update, which is either a
+// failed precondition or elements the set can not hold.
+func writeUpdateError(w http.ResponseWriter, err error) {
+	code := http.StatusBadRequest
+	if errors.Is(err, errPreconditionFailed) {
+		code = http.StatusPreconditionFailed
+	}
+	writeError(w, code, err)
+}
+
+func writeExprError(w http.ResponseWriter, err error) {
+	var exprErr *goset.ExprError
+	if !errors.As(err, &exprErr) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusBadRequest, errorResponse{Error: exprErr.Msg, Position: &exprErr.Pos})
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpset
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zoumo/goset"
+)
+
+type testRequest struct {
+	method  string
+	path    string
+	body    string
+	headers map[string]string
+}
+
+func do(t *testing.T, h http.Handler, req testRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+	for k, v := range req.headers {
+		r.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func Test_Handler(t *testing.T) {
+	registry := goset.NewRegistry()
+	registry.Store("admins", goset.NewSet("alice", "bob"))
+	registry.Store("ids", goset.NewSet(1, 2, 3))
+	h := NewHandler(registry)
+	v := func(name string) string {
+		return etag(registry.Version(name))
+	}
+
+	tests := []struct {
+		name     string
+		req      testRequest
+		wantCode int
+		wantBody string
+		wantETag bool
+	}{
+		{"list", testRequest{"GET", "/sets", "", nil}, 200, `[{"name":"admins","len":2},{"name":"ids","len":3}]`, false},
+		{"get", testRequest{"GET", "/sets/admins", "", nil}, 200, `{"name":"admins","len":2,"elements":["alice","bob"]}`, true},
+		{"get not modified", testRequest{"GET", "/sets/admins", "", map[string]string{"If-None-Match": v("admins")}}, 304, "", true},
+		{"get missing", testRequest{"GET", "/sets/missing", "", nil}, 404, `{"error":"set \"missing\" not found"}`, false},
+		{"len", testRequest{"GET", "/sets/ids/len", "", nil}, 200, `{"len":3}`, true},
+		{"contains int", testRequest{"GET", "/sets/ids/contains?elem=1", "", nil}, 200, `{"contains":true}`, false},
+		{"contains string", testRequest{"GET", `/sets/ids/contains?elem="1"`, "", nil}, 200, `{"contains":false}`, false},
+		{"contains raw string", testRequest{"GET", "/sets/admins/contains?elem=bob", "", nil}, 200, `{"contains":true}`, false},
+		{"contains missing set", testRequest{"GET", "/sets/missing/contains?elem=bob", "", nil}, 200, `{"contains":false}`, false},
+		{"contains no elem", testRequest{"GET", "/sets/ids/contains", "", nil}, 400, `{"error":"exactly one elem parameter is required"}`, false},
+		{"add", testRequest{"POST", "/sets/admins/add", `["carol", "bob"]`, nil}, 200, `{"added":1,"len":3}`, true},
+		{"add creates", testRequest{"POST", "/sets/new/add", `[1, "a"]`, nil}, 200, `{"added":2,"len":2}`, true},
+		{"add invalid", testRequest{"POST", "/sets/new/add", `[[1]]`, nil}, 400, "", false},
+		{"add bad json", testRequest{"POST", "/sets/new/add", `[1,`, nil}, 400, "", false},
+		{"remove", testRequest{"POST", "/sets/new/remove", `[1, 2]`, nil}, 200, `{"len":1,"removed":1}`, true},
+		{"remove all", testRequest{"POST", "/sets/new/remove", `["a"]`, nil}, 200, `{"len":0,"removed":1}`, false},
+		{"union", testRequest{"GET", "/ops/union?set=ids&set=admins&set=missing", "", nil}, 200, `{"len":6,"elements":{"int":[1,2,3],"string":["alice","bob","carol"]}}`, false},
+		{"intersect", testRequest{"GET", "/ops/intersect?set=ids&set=ids", "", nil}, 200, `{"len":3,"elements":[1,2,3]}`, false},
+		{"diff", testRequest{"GET", "/ops/diff?set=admins&set=missing", "", nil}, 200, `{"len":3,"elements":["alice","bob","carol"]}`, false},
+		{"symdiff", testRequest{"GET", "/ops/symdiff?set=ids&set=ids", "", nil}, 200, `{"len":0,"elements":[]}`, false},
+		{"unknown op", testRequest{"GET", "/ops/join?set=ids", "", nil}, 404, `{"error":"unknown operation \"join\""}`, false},
+		{"op without sets", testRequest{"GET", "/ops/union", "", nil}, 400, `{"error":"at least one set parameter is required"}`, false},
+		{"eval", testRequest{"GET", "/eval?expr=" + "admins%20-%20%7B%22bob%22%7D%20%7C%20ids%20%26%20%7B2%7D", "", nil}, 200, `{"len":3,"elements":{"int":[2],"string":["alice","carol"]}}`, false},
+		{"eval error", testRequest{"GET", "/eval?expr=admins%20%26", "", nil}, 400, `{"error":"unexpected end of expression, expected set name, '(' or '{'","position":8}`, false},
+		{"method not allowed", testRequest{"POST", "/sets", "", nil}, 405, `{"error":"method POST not allowed"}`, false},
+		{"not found", testRequest{"GET", "/sets/admins/members", "", nil}, 404, `{"error":"no route for /sets/admins/members"}`, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(t, h, tt.req)
+			if w.Code != tt.wantCode {
+				t.Errorf("%s %s = %v, want %v, body: %s", tt.req.method, tt.req.path, w.Code, tt.wantCode, w.Body)
+			}
+			if got := strings.TrimSpace(w.Body.String()); tt.wantBody != "" && got != tt.wantBody {
+				t.Errorf("%s %s body = %s, want %s", tt.req.method, tt.req.path, got, tt.wantBody)
+			}
+			if got := w.Header().Get("ETag"); (got != "") != tt.wantETag {
+				t.Errorf("%s %s ETag = %q, want ETag %v", tt.req.method, tt.req.path, got, tt.wantETag)
+			}
+		})
+	}
+}
+
+func Test_Handler_Preconditions(t *testing.T) {
+	registry := goset.NewRegistry()
+	h := NewHandler(registry)
+
+	// create only if it does not exist
+	w := do(t, h, testRequest{"PUT", "/sets/a", `[1, 2]`, map[string]string{"If-None-Match": "*"}})
+	if w.Code != 200 || strings.TrimSpace(w.Body.String()) != `{"name":"a","len":2,"elements":[1,2]}` {
+		t.Fatalf("PUT = %v %s", w.Code, w.Body)
+	}
+	tag := w.Header().Get("ETag")
+	if tag != etag(registry.Version("a")) {
+		t.Errorf("PUT ETag = %v, want %v", tag, etag(registry.Version("a")))
+	}
+	if w := do(t, h, testRequest{"PUT", "/sets/a", `[3]`, map[string]string{"If-None-Match": "*"}}); w.Code != 412 {
+		t.Errorf("PUT If-None-Match: * of existing set = %v", w.Code)
+	}
+
+	// the first writer wins
+	w = do(t, h, testRequest{"POST", "/sets/a/add", `[3]`, map[string]string{"If-Match": tag}})
+	if w.Code != 200 || w.Header().Get("ETag") == tag {
+		t.Fatalf("POST add with If-Match = %v, ETag %v", w.Code, w.Header().Get("ETag"))
+	}
+	newTag := w.Header().Get("ETag")
+	for _, req := range []testRequest{
+		{"POST", "/sets/a/add", `[4]`, map[string]string{"If-Match": tag}},
+		{"POST", "/sets/a/remove", `[1]`, map[string]string{"If-Match": tag}},
+		{"PUT", "/sets/a", `[5]`, map[string]string{"If-Match": tag}},
+		{"DELETE", "/sets/a", "", map[string]string{"If-Match": tag}},
+		{"PUT", "/sets/b", `[5]`, map[string]string{"If-Match": "*"}},
+	} {
+		if w := do(t, h, req); w.Code != 412 {
+			t.Errorf("%s %s with stale If-Match = %v, want 412", req.method, req.path, w.Code)
+		}
+	}
+	if s, _ := registry.Get("a"); !s.Equal(goset.NewSet(1, 2, 3)) {
+		t.Errorf("failed preconditions change the set to %v", s)
+	}
+	if _, ok := registry.Get("b"); ok {
+		t.Errorf("failed preconditions create the set")
+	}
+
+	if w := do(t, h, testRequest{"PUT", "/sets/a", `[7]`, map[string]string{"If-Match": `"0", ` + newTag}}); w.Code != 200 {
+		t.Errorf("PUT with one of If-Match = %v", w.Code)
+	}
+	if w := do(t, h, testRequest{"PUT", "/sets/a", `[]`, map[string]string{"If-Match": "*"}}); w.Code != 204 || registry.Len() != 0 {
+		t.Errorf("PUT empty set = %v, want the set deleted", w.Code)
+	}
+	if w := do(t, h, testRequest{"DELETE", "/sets/a", "", nil}); w.Code != 404 {
+		t.Errorf("DELETE missing set = %v", w.Code)
+	}
+	registry.Add("a", 1) //nolint:errcheck
+	if w := do(t, h, testRequest{"DELETE", "/sets/a", "", map[string]string{"If-Match": etag(registry.Version("a"))}}); w.Code != 204 || registry.Len() != 0 {
+		t.Errorf("DELETE = %v, want the set deleted", w.Code)
+	}
+}
+
+func Test_Handler_Errors(t *testing.T) {
+	registry := goset.NewRegistry()
+	registry.Store("bits", goset.NewBitmapSet(1, 2))
+	key := func(elem interface{}) interface{} { return fmt.Sprint(elem) }
+	registry.Store("slices", goset.NewKeyedSet(key, []int{1}, []int{2}))
+	h := NewHandler(registry)
+
+	if w := do(t, h, testRequest{"POST", "/sets/bits/add", `[3, "a"]`, nil}); w.Code != 400 {
+		t.Errorf("POST add of elements the set can not hold = %v, want 400", w.Code)
+	}
+	if s, _ := registry.Get("bits"); !s.Equal(goset.NewSet(1, 2)) {
+		t.Errorf("failed add changes the set to %v", s)
+	}
+	if w := do(t, h, testRequest{"GET", "/ops/symdiff?set=bits&set=slices", "", nil}); w.Code != 500 {
+		t.Errorf("GET symdiff with unhashable elements = %v, want 500", w.Code)
+	}
+}
+
+func Test_Handler_Concurrent(t *testing.T) {
+	registry := goset.NewRegistry()
+	registry.Store("s", goset.NewSet(0))
+	server := httptest.NewServer(NewHandler(registry))
+	defer server.Close()
+
+	// concurrent read-modify-write cycles, each of them retries until its
+	// If-Match wins
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				for {
+					resp, err := http.Get(server.URL + "/sets/s")
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					resp.Body.Close()
+					req, _ := http.NewRequest("POST", server.URL+"/sets/s/add", strings.NewReader(fmt.Sprintf(`["%d-%d"]`, g, i)))
+					req.Header.Set("If-Match", resp.Header.Get("ETag"))
+					resp, err = http.DefaultClient.Do(req)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					resp.Body.Close()
+					if resp.StatusCode == 200 {
+						break
+					}
+					if resp.StatusCode != 412 {
+						t.Errorf("POST add = %v", resp.StatusCode)
+						return
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if s, _ := registry.Get("s"); s.Len() != 41 {
+		t.Errorf("concurrent updates result in %d elements, want 41", s.Len())
+	}
+}
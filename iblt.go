@@ -0,0 +1,177 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ibltHashes is the number of cells each key is stored in.
+const ibltHashes = 3
+
+// ibltCell is a cell of an invertible Bloom lookup table.
+type ibltCell struct {
+	count   int64
+	keySum  uint64
+	hashSum uint64
+}
+
+// iblt is an invertible Bloom lookup table of 64-bit keys. Subtracting the
+// table of another set leaves the keys in the symmetric difference, which
+// can be listed if the table has enough cells for them, about twice the
+// size of the difference for small ones.
+//
+// The cells are split into ibltHashes partitions, and a key is stored in
+// one cell of each partition.
+type iblt struct {
+	cells []ibltCell
+}
+
+// newIBLT returns a new table with at least n cells.
+func newIBLT(n int) *iblt {
+	if n < ibltHashes {
+		n = ibltHashes
+	}
+	n = (n + ibltHashes - 1) / ibltHashes * ibltHashes
+	return &iblt{cells: make([]ibltCell, n)}
+}
+
+// checkHash is stored in hashSum to check whether a cell is pure.
+func checkHash(key uint64) uint64 {
+	return mix64(key ^ 0x5851f42d4c957f2d)
+}
+
+// cellIndex returns the index of the cell of the key in the i-th partition.
+func (t *iblt) cellIndex(key uint64, i int) int {
+	part := uint64(len(t.cells) / ibltHashes)
+	return int(uint64(i)*part + mix64(key+uint64(i+1)*goldenRatio64)%part)
+}
+
+// toggle adds the key to the table n times, n may be negative.
+func (t *iblt) toggle(key uint64, n int64) {
+	check := checkHash(key)
+	for i := 0; i < ibltHashes; i++ {
+		c := &t.cells[t.cellIndex(key, i)]
+		c.count += n
+		c.keySum ^= key
+		c.hashSum ^= check
+	}
+}
+
+func (t *iblt) insert(key uint64) {
+	t.toggle(key, 1)
+}
+
+// subtract subtracts the table b of the same size from t.
+func (t *iblt) subtract(b *iblt) {
+	for i := range t.cells {
+		t.cells[i].count -= b.cells[i].count
+		t.cells[i].keySum ^= b.cells[i].keySum
+		t.cells[i].hashSum ^= b.cells[i].hashSum
+	}
+}
+
+// pure reports whether the cell holds exactly one key.
+func (c *ibltCell) pure() bool {
+	return (c.count == 1 || c.count == -1) && c.hashSum == checkHash(c.keySum)
+}
+
+// decode lists the keys inserted more times in t than subtracted (plus),
+// and the others (minus). It returns false if the table has too few cells
+// for the keys, t is emptied anyway.
+func (t *iblt) decode() (plus, minus []uint64, ok bool) {
+	queue := make([]int, 0, len(t.cells))
+	for i := range t.cells {
+		if t.cells[i].pure() {
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		i := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		c := t.cells[i]
+		if !c.pure() {
+			continue
+		}
+		key := c.keySum
+		if c.count > 0 {
+			plus = append(plus, key)
+		} else {
+			minus = append(minus, key)
+		}
+		t.toggle(key, -c.count)
+		for j := 0; j < ibltHashes; j++ {
+			if k := t.cellIndex(key, j); t.cells[k].pure() {
+				queue = append(queue, k)
+			}
+		}
+	}
+	for _, c := range t.cells {
+		if c.count != 0 || c.keySum != 0 || c.hashSum != 0 {
+			return nil, nil, false
+		}
+	}
+	return plus, minus, true
+}
+
+// appendIBLT appends the binary encoding of t to buf.
+func appendIBLT(buf []byte, t *iblt) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	buf = append(buf, tmp[:binary.PutUvarint(tmp[:], uint64(len(t.cells)))]...)
+	for _, c := range t.cells {
+		buf = append(buf, tmp[:binary.PutVarint(tmp[:], c.count)]...)
+		binary.BigEndian.PutUint64(tmp[:8], c.keySum)
+		buf = append(buf, tmp[:8]...)
+		binary.BigEndian.PutUint64(tmp[:8], c.hashSum)
+		buf = append(buf, tmp[:8]...)
+	}
+	return buf
+}
+
+var errInvalidIBLT = errors.New("goset: invalid IBLT encoding")
+
+// ibltMinCellSize is the minimum size of an encoded cell, a 1-byte count
+// and the two sums.
+const ibltMinCellSize = 17
+
+// readIBLT decodes a table from data, it returns the rest of data.
+func readIBLT(data []byte, maxCells int) (*iblt, []byte, error) {
+	n, size := binary.Uvarint(data)
+	if size <= 0 || n == 0 || n%ibltHashes != 0 || n > uint64(maxCells) {
+		return nil, nil, errInvalidIBLT
+	}
+	data = data[size:]
+	// check the size before allocating the cells
+	if n > uint64(len(data))/ibltMinCellSize {
+		return nil, nil, errInvalidIBLT
+	}
+	t := &iblt{cells: make([]ibltCell, n)}
+	for i := range t.cells {
+		count, size := binary.Varint(data)
+		if size <= 0 || len(data) < size+16 {
+			return nil, nil, errInvalidIBLT
+		}
+		t.cells[i] = ibltCell{
+			count:   count,
+			keySum:  binary.BigEndian.Uint64(data[size:]),
+			hashSum: binary.BigEndian.Uint64(data[size+8:]),
+		}
+		data = data[size+16:]
+	}
+	return t, data, nil
+}
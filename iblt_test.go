@@ -0,0 +1,135 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func sortedKeys(keys []uint64) []uint64 {
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func Test_iblt_decode(t *testing.T) {
+	tests := []struct {
+		name   string
+		cells  int
+		a, b   []uint64
+		wantOK bool
+	}{
+		{"empty", 12, nil, nil, true},
+		{"same keys", 12, []uint64{1, 2, 3}, []uint64{3, 2, 1}, true},
+		{"only plus", 12, []uint64{1, 2, 3}, nil, true},
+		{"only minus", 12, nil, []uint64{4, 5}, true},
+		{"both", 30, []uint64{1, 2, 3, 10, 11}, []uint64{3, 4, 5, 10, 12}, true},
+		{"too small", 3, []uint64{1, 2, 3, 4, 5, 6, 7, 8}, nil, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := newIBLT(tt.cells), newIBLT(tt.cells)
+			for _, key := range tt.a {
+				a.insert(key)
+			}
+			for _, key := range tt.b {
+				b.insert(key)
+			}
+			a.subtract(b)
+			plus, minus, ok := a.decode()
+			if ok != tt.wantOK {
+				t.Fatalf("decode() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			wantPlus := NewSetFrom(toInterfaces64(tt.a)).Diff(NewSetFrom(toInterfaces64(tt.b)))
+			wantMinus := NewSetFrom(toInterfaces64(tt.b)).Diff(NewSetFrom(toInterfaces64(tt.a)))
+			if !NewSetFrom(toInterfaces64(plus)).Equal(wantPlus) || !NewSetFrom(toInterfaces64(minus)).Equal(wantMinus) {
+				t.Errorf("decode() = %v, %v, want %v, %v", plus, minus, wantPlus, wantMinus)
+			}
+		})
+	}
+}
+
+func toInterfaces64(keys []uint64) []interface{} {
+	ret := make([]interface{}, len(keys))
+	for i, key := range keys {
+		ret[i] = key
+	}
+	return ret
+}
+
+func Test_iblt_decode_Random(t *testing.T) {
+	// 2 cells per key are enough in most cases
+	failures := 0
+	for round := 0; round < 100; round++ {
+		table := newIBLT(80)
+		keys := make([]uint64, 0, 40)
+		for i := 0; i < 40; i++ {
+			key := hashElem(round*1000 + i)
+			keys = append(keys, key)
+			table.insert(key)
+		}
+		plus, _, ok := table.decode()
+		if !ok {
+			failures++
+			continue
+		}
+		if !reflect.DeepEqual(sortedKeys(plus), sortedKeys(keys)) {
+			t.Fatalf("decode() = %v, want %v", plus, keys)
+		}
+	}
+	if failures > 5 {
+		t.Errorf("decode() fails %d of 100 times", failures)
+	}
+}
+
+func Test_iblt_Encoding(t *testing.T) {
+	table := newIBLT(10)
+	table.insert(1)
+	table.toggle(2, -1)
+	data := appendIBLT([]byte{}, table)
+	got, rest, err := readIBLT(append(data, 'x'), 100)
+	if err != nil || !reflect.DeepEqual(got, table) || string(rest) != "x" {
+		t.Errorf("readIBLT() = %v, %q, %v", got, rest, err)
+	}
+	for _, data := range [][]byte{
+		{},
+		{0},
+		// not a multiple of the partitions
+		{4},
+		// more than max cells
+		{0xff, 0x01},
+		data[:len(data)-1],
+	} {
+		if _, _, err := readIBLT(data, 100); err != errInvalidIBLT {
+			t.Errorf("readIBLT(%v) error = %v", data, err)
+		}
+	}
+
+	// more cells than data holds, it must fail before allocating them
+	var huge [binary.MaxVarintLen64]byte
+	hostile := append(huge[:binary.PutUvarint(huge[:], 3<<38)], make([]byte, 20)...)
+	if _, _, err := readIBLT(hostile, math.MaxInt); err != errInvalidIBLT {
+		t.Errorf("readIBLT() of %d cells in %d bytes error = %v", uint64(3<<38), len(hostile), err)
+	}
+}
@@ -0,0 +1,526 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"bytes"
+	"fmt"
+	"math/bits"
+)
+
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+// ImmutableSet is a persistent set based on hash array mapped trie (HAMT).
+//
+// An ImmutableSet is never changed after it is created, With, Without,
+// Unite, Intersect and Diff return new versions sharing the unchanged
+// parts of the trie with the old ones. Adding or removing an element takes
+// O(log n) time, and it is safe to read an ImmutableSet concurrently
+// without locking.
+//
+// The zero value is an empty set. The elements must be hashable, adding
+// functions, maps or slices will cause panic.
+type ImmutableSet struct {
+	root *hamtNode
+}
+
+// hamtNode is a node of HAMT, the bitmap records which of the 32 slots have
+// entries, and entries are stored compactly in the order of slots.
+type hamtNode struct {
+	bitmap  uint32
+	size    int
+	entries []hamtEntry
+}
+
+// hamtEntry is either a sub node or a leaf of elements with the same hash.
+type hamtEntry struct {
+	node  *hamtNode
+	hash  uint64
+	elems []interface{}
+}
+
+func (e hamtEntry) len() int {
+	if e.node != nil {
+		return e.node.size
+	}
+	return len(e.elems)
+}
+
+func (e hamtEntry) contains(elem interface{}) bool {
+	for _, x := range e.elems {
+		if x == elem {
+			return true
+		}
+	}
+	return false
+}
+
+func hamtIndex(hash uint64, shift uint) uint32 {
+	return 1 << (uint32(hash>>shift) & hamtMask)
+}
+
+func (n *hamtNode) pos(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func newHamtNode(bitmap uint32, entries []hamtEntry) *hamtNode {
+	if len(entries) == 0 {
+		return nil
+	}
+	n := &hamtNode{bitmap: bitmap, entries: entries}
+	for _, e := range entries {
+		n.size += e.len()
+	}
+	return n
+}
+
+// pairNode returns a node containing two leaves with different hashes.
+func pairNode(a, b hamtEntry, shift uint) *hamtNode {
+	ia, ib := hamtIndex(a.hash, shift), hamtIndex(b.hash, shift)
+	if ia == ib {
+		child := pairNode(a, b, shift+hamtBits)
+		return newHamtNode(ia, []hamtEntry{{node: child}})
+	}
+	if ia > ib {
+		a, b = b, a
+	}
+	return newHamtNode(ia|ib, []hamtEntry{a, b})
+}
+
+func (n *hamtNode) lookup(hash uint64, elem interface{}, shift uint) bool {
+	for n != nil {
+		bit := hamtIndex(hash, shift)
+		if n.bitmap&bit == 0 {
+			return false
+		}
+		e := n.entries[n.pos(bit)]
+		if e.node == nil {
+			return e.hash == hash && e.contains(elem)
+		}
+		n = e.node
+		shift += hamtBits
+	}
+	return false
+}
+
+// replace returns a copy of n with the entry at i replaced.
+func (n *hamtNode) replace(i int, e hamtEntry) *hamtNode {
+	entries := make([]hamtEntry, len(n.entries))
+	copy(entries, n.entries)
+	entries[i] = e
+	return newHamtNode(n.bitmap, entries)
+}
+
+// insert returns a new node with elem inserted, and reports whether elem
+// is added. The returned node is n itself if elem already exists.
+func (n *hamtNode) insert(hash uint64, elem interface{}, shift uint) (*hamtNode, bool) {
+	leaf := hamtEntry{hash: hash, elems: []interface{}{elem}}
+	if n == nil {
+		return newHamtNode(hamtIndex(hash, shift), []hamtEntry{leaf}), true
+	}
+
+	bit := hamtIndex(hash, shift)
+	i := n.pos(bit)
+	if n.bitmap&bit == 0 {
+		entries := make([]hamtEntry, 0, len(n.entries)+1)
+		entries = append(entries, n.entries[:i]...)
+		entries = append(entries, leaf)
+		entries = append(entries, n.entries[i:]...)
+		return newHamtNode(n.bitmap|bit, entries), true
+	}
+
+	e := n.entries[i]
+	switch {
+	case e.node != nil:
+		child, added := e.node.insert(hash, elem, shift+hamtBits)
+		if !added {
+			return n, false
+		}
+		return n.replace(i, hamtEntry{node: child}), true
+	case e.hash == hash:
+		if e.contains(elem) {
+			return n, false
+		}
+		elems := make([]interface{}, len(e.elems), len(e.elems)+1)
+		copy(elems, e.elems)
+		return n.replace(i, hamtEntry{hash: hash, elems: append(elems, elem)}), true
+	default:
+		return n.replace(i, hamtEntry{node: pairNode(e, leaf, shift+hamtBits)}), true
+	}
+}
+
+// remove returns a new node with elem removed, and reports whether elem is
+// removed. The returned node is n itself if elem does not exist.
+func (n *hamtNode) remove(hash uint64, elem interface{}, shift uint) (*hamtNode, bool) {
+	if n == nil {
+		return nil, false
+	}
+	bit := hamtIndex(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	i := n.pos(bit)
+	e := n.entries[i]
+	var replaced hamtEntry
+	if e.node != nil {
+		child, removed := e.node.remove(hash, elem, shift+hamtBits)
+		if !removed {
+			return n, false
+		}
+		replaced = hamtEntry{node: child}
+		// pull the only leaf up to keep the trie compact
+		if child != nil && len(child.entries) == 1 && child.entries[0].node == nil {
+			replaced = child.entries[0]
+		}
+	} else {
+		if e.hash != hash || !e.contains(elem) {
+			return n, false
+		}
+		elems := make([]interface{}, 0, len(e.elems)-1)
+		for _, x := range e.elems {
+			if x != elem {
+				elems = append(elems, x)
+			}
+		}
+		replaced = hamtEntry{hash: hash, elems: elems}
+	}
+
+	if replaced.len() > 0 {
+		return n.replace(i, replaced), true
+	}
+	entries := make([]hamtEntry, 0, len(n.entries)-1)
+	entries = append(entries, n.entries[:i]...)
+	entries = append(entries, n.entries[i+1:]...)
+	return newHamtNode(n.bitmap&^bit, entries), true
+}
+
+func (n *hamtNode) walk(foreach func(elem interface{}) bool) bool {
+	if n == nil {
+		return true
+	}
+	for _, e := range n.entries {
+		if e.node != nil {
+			if !e.node.walk(foreach) {
+				return false
+			}
+			continue
+		}
+		for _, elem := range e.elems {
+			if !foreach(elem) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// insertLeaf inserts all elements of the leaf e into n.
+func (n *hamtNode) insertLeaf(e hamtEntry, shift uint) *hamtNode {
+	for _, elem := range e.elems {
+		n, _ = n.insert(e.hash, elem, shift)
+	}
+	return n
+}
+
+// union returns the union of a and b, it returns a itself if b is a subset
+// of a.
+func union(a, b *hamtNode, shift uint) *hamtNode {
+	if a == nil {
+		return b
+	}
+	if b == nil || a == b {
+		return a
+	}
+
+	bitmap := a.bitmap | b.bitmap
+	entries := make([]hamtEntry, 0, bits.OnesCount32(bitmap))
+	changed := false
+	for rest := bitmap; rest != 0; rest &= rest - 1 {
+		bit := rest & -rest
+		switch {
+		case b.bitmap&bit == 0:
+			entries = append(entries, a.entries[a.pos(bit)])
+		case a.bitmap&bit == 0:
+			entries = append(entries, b.entries[b.pos(bit)])
+			changed = true
+		default:
+			x, y := a.entries[a.pos(bit)], b.entries[b.pos(bit)]
+			e := unionEntry(x, y, shift+hamtBits)
+			changed = changed || e.len() != x.len()
+			entries = append(entries, e)
+		}
+	}
+	if !changed {
+		return a
+	}
+	return newHamtNode(bitmap, entries)
+}
+
+func unionEntry(x, y hamtEntry, shift uint) hamtEntry {
+	switch {
+	case x.node != nil && y.node != nil:
+		return hamtEntry{node: union(x.node, y.node, shift)}
+	case x.node != nil:
+		return hamtEntry{node: x.node.insertLeaf(y, shift)}
+	case y.node != nil:
+		return hamtEntry{node: y.node.insertLeaf(x, shift)}
+	case x.hash != y.hash:
+		return hamtEntry{node: pairNode(x, y, shift)}
+	}
+	elems := x.elems
+	for _, elem := range y.elems {
+		if !x.contains(elem) {
+			if len(elems) == len(x.elems) {
+				elems = append([]interface{}{}, x.elems...)
+			}
+			elems = append(elems, elem)
+		}
+	}
+	return hamtEntry{hash: x.hash, elems: elems}
+}
+
+// intersect returns the intersection of a and b.
+func intersect(a, b *hamtNode, shift uint) *hamtNode {
+	if a == nil || b == nil {
+		return nil
+	}
+	if a == b {
+		return a
+	}
+
+	bitmap := uint32(0)
+	entries := []hamtEntry{}
+	for rest := a.bitmap & b.bitmap; rest != 0; rest &= rest - 1 {
+		bit := rest & -rest
+		x, y := a.entries[a.pos(bit)], b.entries[b.pos(bit)]
+		e := intersectEntry(x, y, shift+hamtBits)
+		if e.len() == 0 {
+			continue
+		}
+		bitmap |= bit
+		entries = append(entries, e)
+	}
+	return newHamtNode(bitmap, entries)
+}
+
+func intersectEntry(x, y hamtEntry, shift uint) hamtEntry {
+	switch {
+	case x.node != nil && y.node != nil:
+		n := intersect(x.node, y.node, shift)
+		if n != nil && len(n.entries) == 1 && n.entries[0].node == nil {
+			return n.entries[0]
+		}
+		return hamtEntry{node: n}
+	case x.node != nil:
+		x, y = y, x
+		fallthrough
+	case y.node != nil:
+		elems := []interface{}{}
+		for _, elem := range x.elems {
+			if y.node.lookup(x.hash, elem, shift) {
+				elems = append(elems, elem)
+			}
+		}
+		return hamtEntry{hash: x.hash, elems: elems}
+	case x.hash != y.hash:
+		return hamtEntry{}
+	}
+	elems := []interface{}{}
+	for _, elem := range x.elems {
+		if y.contains(elem) {
+			elems = append(elems, elem)
+		}
+	}
+	return hamtEntry{hash: x.hash, elems: elems}
+}
+
+// With returns a new version of the set with the given elements added.
+// The set itself is not changed.
+func (s *ImmutableSet) With(elems ...interface{}) *ImmutableSet {
+	root := s.root
+	for i, elem := range elems {
+		if !hashable(elem) {
+			panic(unhashableError(elem, i))
+		}
+		root, _ = root.insert(hashElem(elem), elem, 0)
+	}
+	if root == s.root {
+		return s
+	}
+	return &ImmutableSet{root: root}
+}
+
+// Without returns a new version of the set with the given elements
+// removed. The set itself is not changed.
+func (s *ImmutableSet) Without(elems ...interface{}) *ImmutableSet {
+	root := s.root
+	for _, elem := range elems {
+		if !hashable(elem) {
+			continue
+		}
+		root, _ = root.remove(hashElem(elem), elem, 0)
+	}
+	if root == s.root {
+		return s
+	}
+	return &ImmutableSet{root: root}
+}
+
+// Contains checks whether the given elem is in the set.
+func (s *ImmutableSet) Contains(elem interface{}) bool {
+	if !hashable(elem) {
+		return false
+	}
+	return s.root.lookup(hashElem(elem), elem, 0)
+}
+
+// ContainsAll checks whether all the given elems are in the set.
+func (s *ImmutableSet) ContainsAll(elems ...interface{}) bool {
+	for _, elem := range elems {
+		if !s.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the size of set. aka Cardinality.
+func (s *ImmutableSet) Len() int {
+	if s.root == nil {
+		return 0
+	}
+	return s.root.size
+}
+
+// Range calls f sequentially for each element present in the set.
+// If f returns false, range stops the iteration.
+//
+// The iteration order is decided by the hashes of elements, it is the same
+// for sets containing the same elements.
+func (s *ImmutableSet) Range(foreach func(index int, elem interface{}) bool) {
+	i := 0
+	s.root.walk(func(elem interface{}) bool {
+		if !foreach(i, elem) {
+			return false
+		}
+		i++
+		return true
+	})
+}
+
+// Elements returns all elements in this set.
+func (s *ImmutableSet) Elements() []interface{} {
+	ret := make([]interface{}, 0, s.Len())
+	s.root.walk(func(elem interface{}) bool {
+		ret = append(ret, elem)
+		return true
+	})
+	return ret
+}
+
+// String returns the string representation of the set.
+func (s *ImmutableSet) String() string {
+	buf := bytes.Buffer{}
+	buf.WriteString("ImmutableSet[")
+	s.Range(func(i int, elem interface{}) bool {
+		if i == 0 {
+			buf.WriteString(fmt.Sprintf("%+v", elem))
+		} else {
+			buf.WriteString(fmt.Sprintf(" %+v", elem))
+		}
+		return true
+	})
+	buf.WriteString("]")
+	return buf.String()
+}
+
+// Equal checks whether this set is equal to the given one.
+func (s *ImmutableSet) Equal(b *ImmutableSet) bool {
+	if s.root == b.root {
+		return true
+	}
+	return s.Len() == b.Len() && s.IsSubsetOf(b)
+}
+
+// IsSubsetOf checks whether this set is the subset of the given set.
+func (s *ImmutableSet) IsSubsetOf(b *ImmutableSet) bool {
+	if s.Len() > b.Len() {
+		return false
+	}
+	return s.root.walk(b.Contains)
+}
+
+// Unite returns the union of two sets, aka Union Set. Unchanged parts of
+// the tries are shared with the given sets.
+// math formula: a ∪ b
+func (s *ImmutableSet) Unite(b *ImmutableSet) *ImmutableSet {
+	root := union(s.root, b.root, 0)
+	if root == s.root {
+		return s
+	}
+	return &ImmutableSet{root: root}
+}
+
+// Intersect returns the intersection of two set, aka Intersection Set.
+// math formula: a ∩ b
+func (s *ImmutableSet) Intersect(b *ImmutableSet) *ImmutableSet {
+	return &ImmutableSet{root: intersect(s.root, b.root, 0)}
+}
+
+// Diff returns the difference between the set and this given
+// one, aka Difference Set
+// math formula: a - b
+func (s *ImmutableSet) Diff(b *ImmutableSet) *ImmutableSet {
+	if s.root == b.root {
+		return &ImmutableSet{}
+	}
+	if b.Len() <= s.Len() {
+		return s.Without(b.Elements()...)
+	}
+	ret := &ImmutableSet{}
+	s.root.walk(func(elem interface{}) bool {
+		if !b.Contains(elem) {
+			ret.root, _ = ret.root.insert(hashElem(elem), elem, 0)
+		}
+		return true
+	})
+	return ret
+}
+
+// ToSet returns a new mutable and thread unsafe Set containing all
+// elements in this set.
+func (s *ImmutableSet) ToSet() Set {
+	ret := newSet()
+	s.root.walk(func(elem interface{}) bool {
+		ret.Add(elem) //nolint:errcheck
+		return true
+	})
+	return ret
+}
+
+func newImmutableSetFrom(s Set) *ImmutableSet {
+	ret := &ImmutableSet{}
+	s.Range(func(_ int, elem interface{}) bool {
+		ret.root, _ = ret.root.insert(hashElem(elem), elem, 0)
+		return true
+	})
+	return ret
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func Test_ImmutableSet_With_And_Without(t *testing.T) {
+	empty := &ImmutableSet{}
+	a := empty.With(1, "2", 3.0)
+	b := a.With(4, 1)
+	c := b.Without(1, "x")
+
+	tests := []struct {
+		name string
+		s    *ImmutableSet
+		want Set
+	}{
+		{"empty", empty, NewSet()},
+		{"a", a, NewSet(1, "2", 3.0)},
+		{"b", b, NewSet(1, "2", 3.0, 4)},
+		{"c", c, NewSet("2", 3.0, 4)},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.ToSet(); !got.Equal(tt.want) {
+				t.Errorf("ImmutableSet = %v, want %v", got, tt.want)
+			}
+			if tt.s.Len() != tt.want.Len() {
+				t.Errorf("ImmutableSet.Len() = %v, want %v", tt.s.Len(), tt.want.Len())
+			}
+		})
+	}
+
+	if a.With(1) != a {
+		t.Errorf("ImmutableSet.With() existing element should return the same set")
+	}
+	if a.Without(100) != a {
+		t.Errorf("ImmutableSet.Without() missing element should return the same set")
+	}
+	if !a.Contains(1) || a.Contains(4) || a.Contains([]int{1}) {
+		t.Errorf("ImmutableSet.Contains() gives wrong result")
+	}
+}
+
+func Test_ImmutableSet_With_Unhashable(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("ImmutableSet.With() unhashable element should panic")
+		}
+	}()
+	NewImmutableSet(1, []int{1})
+}
+
+func Test_ImmutableSet_Equality(t *testing.T) {
+	p := &point{1, 2}
+	s := NewImmutableSet(p, 0.0)
+	p.X = 3
+	if !s.Contains(p) {
+		t.Errorf("Contains() of a mutated pointer = false")
+	}
+	if s = s.With(p, math.Copysign(0, -1)); s.Len() != 2 {
+		t.Errorf("Len() = %v, want 2", s.Len())
+	}
+}
+
+func Test_ImmutableSet_Many(t *testing.T) {
+	s := NewImmutableSet()
+	versions := []*ImmutableSet{}
+	for i := 0; i < 2000; i++ {
+		s = s.With(i)
+		if i%500 == 0 {
+			versions = append(versions, s)
+		}
+	}
+	if s.Len() != 2000 {
+		t.Fatalf("ImmutableSet.Len() = %v, want 2000", s.Len())
+	}
+	// old versions are not changed
+	for i, v := range versions {
+		if v.Len() != i*500+1 {
+			t.Errorf("version %d Len() = %v, want %v", i, v.Len(), i*500+1)
+		}
+	}
+	for i := 0; i < 2000; i += 2 {
+		s = s.Without(i)
+	}
+	if s.Len() != 1000 {
+		t.Fatalf("ImmutableSet.Len() = %v, want 1000", s.Len())
+	}
+	for i := 0; i < 2000; i++ {
+		if got := s.Contains(i); got != (i%2 == 1) {
+			t.Errorf("ImmutableSet.Contains(%v) = %v", i, got)
+		}
+	}
+	for i := 1; i < 2000; i += 2 {
+		s = s.Without(i)
+	}
+	if s.Len() != 0 || s.root != nil {
+		t.Errorf("ImmutableSet should be empty, got %v", s)
+	}
+}
+
+func Test_hamtNode_Collision(t *testing.T) {
+	var n *hamtNode
+	// elements with the same hash share one leaf, elements with hashes only
+	// differ in the high bits are pushed down to the deepest level.
+	items := []struct {
+		hash uint64
+		elem interface{}
+	}{
+		{1, "a"},
+		{1, "b"},
+		{1 | 1<<63, "c"},
+		{2, "d"},
+	}
+	for _, it := range items {
+		var added bool
+		if n, added = n.insert(it.hash, it.elem, 0); !added {
+			t.Fatalf("insert(%v) not added", it.elem)
+		}
+	}
+	if n.size != 4 {
+		t.Fatalf("size = %v, want 4", n.size)
+	}
+	for _, it := range items {
+		if !n.lookup(it.hash, it.elem, 0) {
+			t.Errorf("lookup(%v) = false", it.elem)
+		}
+	}
+	if n.lookup(1, "c", 0) {
+		t.Errorf("lookup with wrong hash should be false")
+	}
+
+	m, _ := (*hamtNode)(nil).insert(1|1<<63, "c", 0)
+	m, _ = m.insert(1, "e", 0)
+	u := union(n, m, 0)
+	if u.size != 5 || !u.lookup(1, "e", 0) {
+		t.Errorf("union size = %v, want 5", u.size)
+	}
+	in := intersect(n, m, 0)
+	if in.size != 1 || !in.lookup(1|1<<63, "c", 0) {
+		t.Errorf("intersect size = %v, want 1", in.size)
+	}
+
+	for _, it := range items {
+		var removed bool
+		if n, removed = n.remove(it.hash, it.elem, 0); !removed {
+			t.Fatalf("remove(%v) not removed", it.elem)
+		}
+	}
+	if n != nil {
+		t.Errorf("node should be nil after removing all elements")
+	}
+}
+
+func Test_ImmutableSet_Operations(t *testing.T) {
+	tests := []struct {
+		name          string
+		a             *ImmutableSet
+		b             *ImmutableSet
+		wantUnite     Set
+		wantIntersect Set
+		wantDiff      Set
+	}{
+		{
+			"",
+			NewImmutableSet(1, 2, "3", 4.0),
+			NewImmutableSet(2, "3", 5),
+			NewSet(1, 2, "3", 4.0, 5),
+			NewSet(2, "3"),
+			NewSet(1, 4.0),
+		},
+		{
+			"empty",
+			NewImmutableSet(1, 2),
+			NewImmutableSet(),
+			NewSet(1, 2),
+			NewSet(),
+			NewSet(1, 2),
+		},
+		{
+			"large",
+			NewImmutableSetFrom(NewSetFromInts(rangeInts(0, 300))),
+			NewImmutableSetFrom(NewSetFromInts(rangeInts(200, 1000))),
+			NewSetFromInts(rangeInts(0, 1000)),
+			NewSetFromInts(rangeInts(200, 300)),
+			NewSetFromInts(rangeInts(0, 200)),
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Unite(tt.b); !got.ToSet().Equal(tt.wantUnite) || got.Len() != tt.wantUnite.Len() {
+				t.Errorf("ImmutableSet.Unite() = %v, want %v", got, tt.wantUnite)
+			}
+			if got := tt.b.Unite(tt.a); !got.ToSet().Equal(tt.wantUnite) {
+				t.Errorf("ImmutableSet.Unite() = %v, want %v", got, tt.wantUnite)
+			}
+			if got := tt.a.Intersect(tt.b); !got.ToSet().Equal(tt.wantIntersect) || got.Len() != tt.wantIntersect.Len() {
+				t.Errorf("ImmutableSet.Intersect() = %v, want %v", got, tt.wantIntersect)
+			}
+			if got := tt.a.Diff(tt.b); !got.ToSet().Equal(tt.wantDiff) || got.Len() != tt.wantDiff.Len() {
+				t.Errorf("ImmutableSet.Diff() = %v, want %v", got, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func Test_ImmutableSet_StructuralSharing(t *testing.T) {
+	a := NewImmutableSetFrom(NewSetFromInts(rangeInts(0, 1000)))
+	b := a.With(1000)
+	shared := 0
+	for i := range a.root.entries {
+		if a.root.entries[i].node != nil && a.root.entries[i].node == b.root.entries[i].node {
+			shared++
+		}
+	}
+	if shared < len(a.root.entries)-1 {
+		t.Errorf("ImmutableSet.With() shares %d of %d sub nodes", shared, len(a.root.entries))
+	}
+	if a.Unite(a.Without(5)) != a {
+		t.Errorf("ImmutableSet.Unite() with its subset should return itself")
+	}
+	if !a.Equal(b.Without(1000)) || a.Equal(b) {
+		t.Errorf("ImmutableSet.Equal() gives wrong result")
+	}
+	if !a.IsSubsetOf(b) || b.IsSubsetOf(a) {
+		t.Errorf("ImmutableSet.IsSubsetOf() gives wrong result")
+	}
+}
+
+func Test_ImmutableSet_Concurrent(t *testing.T) {
+	s := NewImmutableSet(1, 2, 3)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			local := s
+			for i := 0; i < 100; i++ {
+				local = local.With(g*1000 + 100 + i)
+				if !s.Contains(1) || s.Len() != 3 {
+					t.Errorf("shared ImmutableSet is changed")
+					return
+				}
+			}
+			if local.Len() != 103 {
+				t.Errorf("local ImmutableSet.Len() = %v, want 103", local.Len())
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func Test_ImmutableSet_String_And_Range(t *testing.T) {
+	s := NewImmutableSet(1)
+	if got := s.String(); got != "ImmutableSet[1]" {
+		t.Errorf("ImmutableSet.String() = %v", got)
+	}
+	s = s.With(2, 3)
+	n := 0
+	s.Range(func(i int, elem interface{}) bool {
+		n++
+		return i < 1
+	})
+	if n != 2 {
+		t.Errorf("ImmutableSet.Range() visited %d elements, want 2", n)
+	}
+	if len(s.Elements()) != 3 {
+		t.Errorf("ImmutableSet.Elements() = %v", s.Elements())
+	}
+}
+
+func rangeInts(lo, hi int) []int {
+	ret := make([]int, 0, hi-lo)
+	for i := lo; i < hi; i++ {
+		ret = append(ret, i)
+	}
+	return ret
+}
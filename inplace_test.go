@@ -0,0 +1,178 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func Test_InPlaceOperations(t *testing.T) {
+	impls := []struct {
+		name string
+		new  func(elems ...int) Set
+	}{
+		{"set", func(elems ...int) Set { return NewSetFromInts(elems) }},
+		{"safe", func(elems ...int) Set { return NewSafeSetFromInts(elems) }},
+		{"ordered", func(elems ...int) Set { return NewOrderedSetFrom(elems) }},
+		{"sorted", func(elems ...int) Set { return NewSortedSet(toInterfaces(elems)...) }},
+		{"bitmap", func(elems ...int) Set { return NewBitmapSet(elems...) }},
+		{"sharded", func(elems ...int) Set { return NewShardedSet(4, toInterfaces(elems)...) }},
+		{"keyed", func(elems ...int) Set { return NewKeyedSet(nil, toInterfaces(elems)...) }},
+	}
+	a := []int{1, 2, 3, 4, 100, 200}
+	b := []int{3, 4, 5, 200, 300}
+
+	ops := []struct {
+		name    string
+		inPlace func(s, b Set)
+		want    func(s, b Set) Set
+	}{
+		{"UniteWith", func(s, b Set) { s.UniteWith(b) }, Set.Unite},
+		{"IntersectWith", func(s, b Set) { s.IntersectWith(b) }, Set.Intersect},
+		{"DiffWith", func(s, b Set) { s.DiffWith(b) }, Set.Diff},
+		{"SymmetricDiffWith", func(s, b Set) { s.SymmetricDiffWith(b) }, Set.SymmetricDiff},
+	}
+	for _, x := range impls {
+		for _, y := range impls {
+			for _, op := range ops {
+				x, y, op := x, y, op
+				t.Run(x.name+"/"+y.name+"/"+op.name, func(t *testing.T) {
+					s := x.new(a...)
+					want := op.want(x.new(a...), y.new(b...))
+					op.inPlace(s, y.new(b...))
+					if !s.Equal(want) || s.Len() != want.Len() {
+						t.Errorf("%s() = %v, want %v", op.name, s, want)
+					}
+				})
+			}
+		}
+	}
+
+	// operations with the set itself
+	for _, x := range impls {
+		x := x
+		t.Run(x.name+"/self", func(t *testing.T) {
+			s := x.new(a...)
+			s.UniteWith(s)
+			s.IntersectWith(s)
+			if s.Len() != len(a) {
+				t.Errorf("UniteWith and IntersectWith with itself = %v", s)
+			}
+			s.SymmetricDiffWith(s)
+			if s.Len() != 0 {
+				t.Errorf("SymmetricDiffWith() with itself = %v", s)
+			}
+			s = x.new(a...)
+			s.DiffWith(s)
+			if s.Len() != 0 {
+				t.Errorf("DiffWith() with itself = %v", s)
+			}
+		})
+	}
+}
+
+func toInterfaces(elems []int) []interface{} {
+	ret := make([]interface{}, len(elems))
+	for i, elem := range elems {
+		ret[i] = elem
+	}
+	return ret
+}
+
+func Test_InPlaceOperations_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Set
+	}{
+		{"sorted", NewSortedSet(1, 2)},
+		{"bitmap", NewBitmapSet(1, 2)},
+		{"safe bitmap", NewSafeBitmapSet(1, 2)},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewSet(3, 1.5)
+			if err := tt.s.UniteWith(b); err == nil {
+				t.Errorf("UniteWith() error = nil")
+			}
+			if err := tt.s.SymmetricDiffWith(b); err == nil {
+				t.Errorf("SymmetricDiffWith() error = nil")
+			}
+			if !tt.s.Equal(NewSet(1, 2)) {
+				t.Errorf("failed operations change the set to %v", tt.s)
+			}
+		})
+	}
+}
+
+func Test_InPlaceOperations_Unhashable(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Set
+	}{
+		{"set", NewSet(1, 2)},
+		{"ordered", NewOrderedSet(1, 2)},
+		{"safe ordered", NewSafeOrderedSet(1, 2)},
+		{"sharded", NewShardedSet(4, 1, 2)},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			// keyed sets hold elements which are not hashable
+			b := NewKeyedSet(nil, 1, keyedUser{7, []string{"a"}})
+			var e *UnhashableError
+			if err := tt.s.UniteWith(b); !errors.As(err, &e) {
+				t.Errorf("UniteWith() error = %v, want *UnhashableError", err)
+			}
+			if err := tt.s.SymmetricDiffWith(b); !errors.As(err, &e) {
+				t.Errorf("SymmetricDiffWith() error = %v, want *UnhashableError", err)
+			}
+			if !tt.s.Equal(NewSet(1, 2)) {
+				t.Errorf("failed operations change the set to %v", tt.s)
+			}
+		})
+	}
+}
+
+func Test_threadSafeSet_InPlace_Concurrent(t *testing.T) {
+	a := NewSafeSet(1, 2, 3)
+	b := NewShardedSet(4, 2, 3, 4)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				a.UniteWith(b)
+				a.Equal(b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				b.UniteWith(a)
+				b.IsSubsetOf(a)
+			}
+		}()
+	}
+	wg.Wait()
+	if !a.Equal(NewSet(1, 2, 3, 4)) || !b.Equal(a) {
+		t.Errorf("UniteWith() concurrently got %v and %v", a, b)
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// taggedElements is the JSON representation of a set whose elements are
+// not all ints or all strings. Elements are grouped by their types so that
+// decoding can restore them into the right typed set.
+type taggedElements struct {
+	Ints    []int         `json:"int,omitempty"`
+	Strings []string      `json:"string,omitempty"`
+	Floats  []float64     `json:"float64,omitempty"`
+	Others  []interface{} `json:"any,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// A set containing only ints or only strings is encoded as a plain JSON
+// array, e.g. [1,2,3]. Other sets are encoded in the type-tagged form,
+// e.g. {"int":[1],"string":["a"],"float64":[1.5],"any":[true]}.
+func (s *set) MarshalJSON() ([]byte, error) {
+	elems := s.Elements()
+	// make the output stable
+	sortElements(elems)
+	return marshalElements(elems)
+}
+
+// sortElements sorts elems in a stable order: ints and floats are sorted
+// numerically, strings lexically, the others by their representations.
+func sortElements(elems []interface{}) {
+	rank := func(elem interface{}) int {
+		switch elem.(type) {
+		case int:
+			return 0
+		case string:
+			return 1
+		case float64:
+			return 2
+		}
+		return 3
+	}
+	sort.Slice(elems, func(i, j int) bool {
+		x, y := elems[i], elems[j]
+		if rx, ry := rank(x), rank(y); rx != ry {
+			return rx < ry
+		}
+		switch xv := x.(type) {
+		case int:
+			return xv < y.(int)
+		case string:
+			return xv < y.(string)
+		case float64:
+			return xv < y.(float64)
+		}
+		return fmt.Sprintf("%T%v", x, x) < fmt.Sprintf("%T%v", y, y)
+	})
+}
+
+// marshalElements encodes elems as a plain JSON array if they are all ints
+// or all strings, otherwise in the type-tagged form. The order of elems is
+// kept in each JSON array.
+func marshalElements(elems []interface{}) ([]byte, error) {
+	tagged := taggedElements{}
+	for _, elem := range elems {
+		switch e := elem.(type) {
+		case int:
+			tagged.Ints = append(tagged.Ints, e)
+		case string:
+			tagged.Strings = append(tagged.Strings, e)
+		case float64:
+			tagged.Floats = append(tagged.Floats, e)
+		default:
+			tagged.Others = append(tagged.Others, e)
+		}
+	}
+
+	if len(tagged.Ints) == len(elems) || len(tagged.Strings) == len(elems) {
+		if elems == nil {
+			elems = []interface{}{}
+		}
+		return json.Marshal(elems)
+	}
+	return json.Marshal(tagged)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces all elements in
+// the set with the decoded ones.
+//
+// Both the plain JSON array and the type-tagged form written by MarshalJSON
+// are accepted. In a plain array, integral numbers are decoded as int and
+// the other numbers as float64. In the type-tagged form, elements are
+// restored into the typed set named by their tags, and numbers tagged by
+// "any" are decoded as float64.
+func (s *set) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalElements(data)
+	if err != nil {
+		return err
+	}
+	if elems == nil {
+		return nil
+	}
+	group := newTypedSetGroup()
+	if err := group.Add(elems...); err != nil {
+		return err
+	}
+	s.typedSetGroup = group
+	return nil
+}
+
+// unmarshalElements decodes elements from the plain JSON array or the
+// type-tagged form. It returns nil if data is JSON null.
+func unmarshalElements(data []byte) ([]interface{}, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	if len(data) > 0 && data[0] == '{' {
+		tagged := taggedElements{}
+		if err := json.Unmarshal(data, &tagged); err != nil {
+			return nil, err
+		}
+		elems := make([]interface{}, 0, len(tagged.Ints)+len(tagged.Strings)+len(tagged.Floats)+len(tagged.Others))
+		for _, elem := range tagged.Ints {
+			elems = append(elems, elem)
+		}
+		for _, elem := range tagged.Strings {
+			elems = append(elems, elem)
+		}
+		for _, elem := range tagged.Floats {
+			elems = append(elems, elem)
+		}
+		elems = append(elems, tagged.Others...)
+		return elems, nil
+	}
+
+	var raw []interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	elems := make([]interface{}, 0, len(raw))
+	for _, elem := range raw {
+		number, ok := elem.(json.Number)
+		if !ok {
+			elems = append(elems, elem)
+			continue
+		}
+		if i, err := number.Int64(); err == nil && int64(int(i)) == i {
+			elems = append(elems, int(i))
+			continue
+		}
+		f, err := number.Float64()
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, f)
+	}
+	return elems, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *threadSafeSet) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return json.Marshal(s.unsafe)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *threadSafeSet) UnmarshalJSON(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.unsafe == nil {
+		s.unsafe = newSet()
+	}
+	return json.Unmarshal(data, s.unsafe)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_set_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Set
+		want string
+	}{
+		{"empty", NewSet(), `[]`},
+		{"ints", NewSet(3, 1, 2), `[1,2,3]`},
+		{"strings", NewSafeSet("b", "a"), `["a","b"]`},
+		{"mixed", NewSet(2, 1, "a", 1.5, 2.0), `{"int":[1,2],"string":["a"],"float64":[1.5,2]}`},
+		{"any", NewSet(true, nil), `{"any":[null,true]}`},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.s)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_set_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       Set
+		data    string
+		want    Set
+		wantErr bool
+	}{
+		{"ints", NewSet(), `[1,2,3]`, NewSet(1, 2, 3), false},
+		{"strings", NewSafeSet(), `["a","b"]`, NewSet("a", "b"), false},
+		{"plain mixed", NewSet(), `[1,"a",1.5,true,null]`, NewSet(1, "a", 1.5, true, nil), false},
+		{"tagged", NewSet(), `{"int":[1,2],"string":["a"],"float64":[1.5,2]}`, NewSet(1, 2, "a", 1.5, 2.0), false},
+		{"replace", NewSet(4, 5), `[1]`, NewSet(1), false},
+		{"null", NewSet(4, 5), `null`, NewSet(4, 5), false},
+		{"unhashable", NewSet(), `[[1]]`, nil, true},
+		{"invalid", NewSet(), `1`, nil, true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(tt.data), tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("json.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !tt.s.Equal(tt.want) {
+				t.Errorf("json.Unmarshal() = %v, want %v", tt.s, tt.want)
+			}
+		})
+	}
+}
+
+func Test_set_JSON_RoundTrip(t *testing.T) {
+	type config struct {
+		Hosts Set `json:"hosts"`
+	}
+
+	in := config{Hosts: NewSet(1, "1", 1.0, 2.5, false)}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	out := config{Hosts: NewSafeSet()}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !out.Hosts.Equal(in.Hosts) {
+		t.Errorf("round trip = %v, want %v", out.Hosts, in.Hosts)
+	}
+	if !out.Hosts.Contains(1.0) || !out.Hosts.Contains(1) {
+		t.Errorf("round trip lost element types: %v", out.Hosts)
+	}
+}
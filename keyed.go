@@ -0,0 +1,354 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import "sort"
+
+// Keyer is implemented by elements which are not hashable, such as structs
+// containing slices. A keyed set buckets them by the keys returned from
+// SetKey, the keys must be hashable.
+type Keyer interface {
+	SetKey() interface{}
+}
+
+// KeyFunc returns the key of the given element, elements with the same key
+// are considered as the same element in a keyed set.
+type KeyFunc func(elem interface{}) interface{}
+
+// keyOf is the default KeyFunc. It returns SetKey() of Keyer elements, and
+// the element itself for the others.
+func keyOf(elem interface{}) interface{} {
+	if k, ok := elem.(Keyer); ok {
+		return k.SetKey()
+	}
+	return elem
+}
+
+// keyedSet maps the keys of elements to the original elements.
+type keyedSet struct {
+	key   KeyFunc
+	elems map[interface{}]interface{}
+}
+
+func newKeyedSet(key KeyFunc, elems ...interface{}) *keyedSet {
+	if key == nil {
+		key = keyOf
+	}
+	s := &keyedSet{
+		key:   key,
+		elems: make(map[interface{}]interface{}),
+	}
+	if err := s.Add(elems...); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (s *keyedSet) empty() *keyedSet {
+	return &keyedSet{
+		key:   s.key,
+		elems: make(map[interface{}]interface{}),
+	}
+}
+
+// keyErr returns the key of elem, or an *UnhashableError if the key is not
+// hashable.
+func (s *keyedSet) keyErr(elem interface{}, index int) (interface{}, error) {
+	k := s.key(elem)
+	if !hashable(k) {
+		return nil, unhashableError(elem, index)
+	}
+	return k, nil
+}
+
+// index returns the elements of b mapped by the keys derived from the key
+// function of s.
+func (s *keyedSet) index(b Set) map[interface{}]interface{} {
+	b = b.ToThreadUnsafe()
+	if kb, ok := b.(*keyedSet); ok && kb == s {
+		return s.elems
+	}
+	ret := make(map[interface{}]interface{}, b.Len())
+	b.Range(func(_ int, elem interface{}) bool {
+		if k, err := s.keyErr(elem, 0); err == nil {
+			ret[k] = elem
+		}
+		return true
+	})
+	return ret
+}
+
+func (s *keyedSet) Add(elems ...interface{}) error {
+	keys := make([]interface{}, len(elems))
+	for i, elem := range elems {
+		k, err := s.keyErr(elem, i)
+		if err != nil {
+			return err
+		}
+		keys[i] = k
+	}
+	for i, k := range keys {
+		if _, ok := s.elems[k]; !ok {
+			s.elems[k] = elems[i]
+		}
+	}
+	return nil
+}
+
+func (s *keyedSet) Extend(b interface{}) error {
+	return extend(s, b)
+}
+
+func (s *keyedSet) Remove(elems ...interface{}) {
+	for _, elem := range elems {
+		if k, err := s.keyErr(elem, 0); err == nil {
+			delete(s.elems, k)
+		}
+	}
+}
+
+func (s *keyedSet) Contains(elem interface{}) bool {
+	ok, _ := s.ContainsErr(elem)
+	return ok
+}
+
+func (s *keyedSet) ContainsErr(elem interface{}) (bool, error) {
+	k, err := s.keyErr(elem, 0)
+	if err != nil {
+		return false, err
+	}
+	_, ok := s.elems[k]
+	return ok, nil
+}
+
+func (s *keyedSet) ContainsAll(elems ...interface{}) bool {
+	for _, elem := range elems {
+		if !s.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *keyedSet) ContainsAny(elems ...interface{}) bool {
+	for _, elem := range elems {
+		if s.Contains(elem) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *keyedSet) Copy() Set {
+	ret := s.empty()
+	for k, elem := range s.elems {
+		ret.elems[k] = elem
+	}
+	return ret
+}
+
+func (s *keyedSet) Len() int {
+	return len(s.elems)
+}
+
+func (s *keyedSet) String() string {
+	return formatSet(s)
+}
+
+func (s *keyedSet) Range(foreach func(index int, elem interface{}) bool) {
+	i := 0
+	for _, elem := range s.elems {
+		if !foreach(i, elem) {
+			return
+		}
+		i++
+	}
+}
+
+func (s *keyedSet) Elements() []interface{} {
+	ret := make([]interface{}, 0, len(s.elems))
+	for _, elem := range s.elems {
+		ret = append(ret, elem)
+	}
+	return ret
+}
+
+func (s *keyedSet) ToInts() []int {
+	ret := []int{}
+	for _, elem := range s.elems {
+		if i, ok := elem.(int); ok {
+			ret = append(ret, i)
+		}
+	}
+	sort.Ints(ret)
+	return ret
+}
+
+func (s *keyedSet) ToStrings() []string {
+	ret := []string{}
+	for _, elem := range s.elems {
+		if str, ok := elem.(string); ok {
+			ret = append(ret, str)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func (s *keyedSet) ToThreadUnsafe() Set {
+	return s
+}
+
+func (s *keyedSet) ToThreadSafe() Set {
+	return &threadSafeSet{unsafe: s}
+}
+
+func (s *keyedSet) Equal(b Set) bool {
+	bi := s.index(b)
+	return len(s.elems) == len(bi) && isSubsetOfKeys(s.elems, bi)
+}
+
+func (s *keyedSet) IsSubsetOf(b Set) bool {
+	return isSubsetOfKeys(s.elems, s.index(b))
+}
+
+func (s *keyedSet) IsSupersetOf(b Set) bool {
+	return isSubsetOfKeys(s.index(b), s.elems)
+}
+
+func isSubsetOfKeys(a, b map[interface{}]interface{}) bool {
+	if len(a) > len(b) {
+		return false
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *keyedSet) Diff(b Set) Set {
+	bi := s.index(b)
+	ret := s.empty()
+	for k, elem := range s.elems {
+		if _, ok := bi[k]; !ok {
+			ret.elems[k] = elem
+		}
+	}
+	return ret
+}
+
+func (s *keyedSet) SymmetricDiff(b Set) Set {
+	bi := s.index(b)
+	ret := s.empty()
+	for k, elem := range s.elems {
+		if _, ok := bi[k]; !ok {
+			ret.elems[k] = elem
+		}
+	}
+	for k, elem := range bi {
+		if _, ok := s.elems[k]; !ok {
+			ret.elems[k] = elem
+		}
+	}
+	return ret
+}
+
+func (s *keyedSet) Unite(b Set) Set {
+	ret := s.Copy().(*keyedSet)
+	for k, elem := range s.index(b) {
+		if _, ok := ret.elems[k]; !ok {
+			ret.elems[k] = elem
+		}
+	}
+	return ret
+}
+
+func (s *keyedSet) Intersect(b Set) Set {
+	bi := s.index(b)
+	ret := s.empty()
+	for k, elem := range s.elems {
+		if _, ok := bi[k]; ok {
+			ret.elems[k] = elem
+		}
+	}
+	return ret
+}
+
+func (s *keyedSet) UniteWith(b Set) error {
+	for k, elem := range s.index(b) {
+		if _, ok := s.elems[k]; !ok {
+			s.elems[k] = elem
+		}
+	}
+	return nil
+}
+
+func (s *keyedSet) IntersectWith(b Set) {
+	bi := s.index(b)
+	for k := range s.elems {
+		if _, ok := bi[k]; !ok {
+			delete(s.elems, k)
+		}
+	}
+}
+
+func (s *keyedSet) DiffWith(b Set) {
+	for k := range s.index(b) {
+		delete(s.elems, k)
+	}
+}
+
+func (s *keyedSet) SymmetricDiffWith(b Set) error {
+	for k, elem := range s.index(b) {
+		if _, ok := s.elems[k]; ok {
+			delete(s.elems, k)
+		} else {
+			s.elems[k] = elem
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *keyedSet) MarshalJSON() ([]byte, error) {
+	elems := s.Elements()
+	sortElements(elems)
+	return marshalElements(elems)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces all elements in
+// the set with the decoded ones, the key function is kept.
+func (s *keyedSet) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalElements(data)
+	if err != nil {
+		return err
+	}
+	if elems == nil {
+		return nil
+	}
+	if s.key == nil {
+		s.key = keyOf
+	}
+	ret := s.empty()
+	if err := ret.Add(elems...); err != nil {
+		return err
+	}
+	*s = *ret
+	return nil
+}
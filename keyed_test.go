@@ -0,0 +1,202 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type keyedUser struct {
+	ID   int
+	Tags []string
+}
+
+func (u keyedUser) SetKey() interface{} {
+	return u.ID
+}
+
+func Test_keyedSet_Keyer(t *testing.T) {
+	s := NewKeyedSet(nil, keyedUser{1, []string{"a"}}, keyedUser{2, nil}, keyedUser{1, []string{"b"}}, "x")
+	if s.Len() != 3 {
+		t.Fatalf("keyedSet.Len() = %v, want 3, got %v", s.Len(), s)
+	}
+	if !s.Contains(keyedUser{ID: 1}) || s.Contains(keyedUser{ID: 3}) || !s.Contains("x") {
+		t.Errorf("keyedSet.Contains() gives wrong result")
+	}
+	found := false
+	s.Range(func(_ int, elem interface{}) bool {
+		if u, ok := elem.(keyedUser); ok && u.ID == 1 {
+			found = len(u.Tags) == 1 && u.Tags[0] == "a"
+		}
+		return true
+	})
+	if !found {
+		t.Errorf("keyedSet.Range() should return the first added element, got %v", s)
+	}
+	if got := s.ToStrings(); len(got) != 1 || got[0] != "x" {
+		t.Errorf("keyedSet.ToStrings() = %v", got)
+	}
+
+	err := s.Add(keyedUser{ID: 4}, []int{1})
+	var e *UnhashableError
+	if !errors.As(err, &e) || e.Index != 1 {
+		t.Errorf("keyedSet.Add() error = %v, want unhashable at index 1", err)
+	}
+	if s.Len() != 3 {
+		t.Errorf("keyedSet.Add() is not all-or-nothing, got %v", s)
+	}
+	s.Remove(keyedUser{ID: 2}, []int{1})
+	if s.Len() != 2 {
+		t.Errorf("keyedSet.Remove() got %v", s)
+	}
+}
+
+func Test_keyedSet_KeyFunc(t *testing.T) {
+	key := func(elem interface{}) interface{} {
+		return fmt.Sprint(elem)
+	}
+	s := NewKeyedSet(key, []int{1, 2}, []int{1, 2}, []int{3})
+	if s.Len() != 2 {
+		t.Fatalf("keyedSet.Len() = %v, want 2", s.Len())
+	}
+	if !s.Contains([]int{3}) {
+		t.Errorf("keyedSet.Contains() = false")
+	}
+	if ok, err := s.ContainsErr([]int{4}); ok || err != nil {
+		t.Errorf("keyedSet.ContainsErr() = %v, %v", ok, err)
+	}
+}
+
+func Test_keyedSet_Operations(t *testing.T) {
+	u := func(ids ...int) []interface{} {
+		ret := []interface{}{}
+		for _, id := range ids {
+			ret = append(ret, keyedUser{ID: id, Tags: []string{}})
+		}
+		return ret
+	}
+	a := NewKeyedSet(nil, u(1, 2, 3)...)
+	b := NewKeyedSet(nil, u(2, 3, 4)...)
+
+	tests := []struct {
+		name string
+		got  Set
+		want Set
+	}{
+		{"Diff", a.Diff(b), NewKeyedSet(nil, u(1)...)},
+		{"SymmetricDiff", a.SymmetricDiff(b), NewKeyedSet(nil, u(1, 4)...)},
+		{"Unite", a.Unite(b), NewKeyedSet(nil, u(1, 2, 3, 4)...)},
+		{"Intersect", a.Intersect(b), NewKeyedSet(nil, u(2, 3)...)},
+		{"Copy", a.Copy(), a},
+		{"safe Unite", NewSafeKeyedSet(nil, u(1)...).Unite(NewSafeKeyedSet(nil, u(2)...)), NewKeyedSet(nil, u(1, 2)...)},
+		{"with plain set", NewKeyedSet(nil, 1, 2).Unite(NewSet(2, 3)), NewSet(1, 2, 3)},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.got.Equal(tt.want) {
+				t.Errorf("keyedSet.%s() = %v, want %v", tt.name, tt.got, tt.want)
+			}
+			if _, ok := tt.got.ToThreadUnsafe().(*keyedSet); !ok {
+				t.Errorf("keyedSet.%s() returns %T", tt.name, tt.got)
+			}
+		})
+	}
+	if !a.Intersect(b).IsSubsetOf(a) || !a.IsSupersetOf(a.Diff(b)) || a.IsSubsetOf(b) {
+		t.Errorf("keyedSet.IsSubsetOf() gives wrong result")
+	}
+}
+
+func Test_set_With_keyedSet(t *testing.T) {
+	u1, u2 := keyedUser{1, []string{"a"}}, keyedUser{2, nil}
+	keyed := func() Set { return NewKeyedSet(nil, u1, u2, 3) }
+
+	tests := []struct {
+		name string
+		s    Set
+	}{
+		{"set", NewSet(3, 4)},
+		{"thread safe set", NewSafeSet(3, 4)},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			b := keyed()
+			if tt.s.Equal(b) || NewSet().Equal(b) || !NewSet().IsSubsetOf(b) {
+				t.Errorf("Equal() and IsSubsetOf() with keyed set give wrong results")
+			}
+			if tt.s.IsSupersetOf(b) || NewSafeSet().IsSupersetOf(b) || tt.s.IsSubsetOf(b) {
+				t.Errorf("IsSupersetOf() and IsSubsetOf() with keyed set give wrong results")
+			}
+			if got := tt.s.Diff(b); !got.Equal(NewSet(4)) {
+				t.Errorf("Diff() = %v, want {4}", got)
+			}
+			if got := tt.s.Intersect(b); !got.Equal(NewSet(3)) {
+				t.Errorf("Intersect() = %v, want {3}", got)
+			}
+			if got := tt.s.Unite(b); got.Len() != 4 || !got.ContainsAll(u1, u2, 3, 4) {
+				t.Errorf("Unite() = %v, want 4 elements", got)
+			}
+			if got := tt.s.SymmetricDiff(b); got.Len() != 3 || !got.ContainsAll(u1, u2, 4) {
+				t.Errorf("SymmetricDiff() = %v, want 3 elements", got)
+			}
+
+			// in-place operations never drop elements silently
+			c := tt.s.Copy()
+			var e *UnhashableError
+			if err := c.UniteWith(b); !errors.As(err, &e) || !c.Equal(tt.s) {
+				t.Errorf("UniteWith() error = %v, set = %v, want unhashable and unchanged", err, c)
+			}
+			if err := c.SymmetricDiffWith(b); !errors.As(err, &e) || !c.Equal(tt.s) {
+				t.Errorf("SymmetricDiffWith() error = %v, set = %v, want unhashable and unchanged", err, c)
+			}
+			c.IntersectWith(b)
+			if !c.Equal(NewSet(3)) {
+				t.Errorf("IntersectWith() = %v, want {3}", c)
+			}
+			c = tt.s.Copy()
+			c.DiffWith(b)
+			if !c.Equal(NewSet(4)) {
+				t.Errorf("DiffWith() = %v, want {4}", c)
+			}
+			if err := c.UniteWith(NewKeyedSet(nil, 5)); err != nil || !c.Equal(NewSet(4, 5)) {
+				t.Errorf("UniteWith() = %v, %v, want {4, 5}", c, err)
+			}
+		})
+	}
+}
+
+func Test_keyedSet_JSON(t *testing.T) {
+	s := NewKeyedSet(nil, 2, "a", 1)
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != `{"int":[1,2],"string":["a"]}` {
+		t.Errorf("json.Marshal() = %s", data)
+	}
+	got := NewKeyedSet(nil)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !got.Equal(s) {
+		t.Errorf("json.Unmarshal() = %v, want %v", got, s)
+	}
+}
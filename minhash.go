@@ -0,0 +1,217 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// MinHash is the MinHash signature of a set. Each value is the minimum of a
+// hash function over the elements, the probability that two signatures
+// agree at a position is the Jaccard index of the two sets.
+type MinHash []uint64
+
+// minHashSeed returns the seed of the i-th hash function.
+func minHashSeed(i int) uint64 {
+	return mix64(uint64(i+1) * goldenRatio64)
+}
+
+func newMinHash(s Set, size int) MinHash {
+	sig := make(MinHash, size)
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	s.Range(func(_ int, elem interface{}) bool {
+		h := hashElem(elem)
+		for i := range sig {
+			if v := mix64(h ^ minHashSeed(i)); v < sig[i] {
+				sig[i] = v
+			}
+		}
+		return true
+	})
+	return sig
+}
+
+// Jaccard estimates the Jaccard index of the two sets by the fraction of
+// positions where the signatures agree. The standard error is about
+// 1/sqrt(len(m)). The signatures must have the same size.
+func (m MinHash) Jaccard(b MinHash) (float64, error) {
+	if len(m) != len(b) {
+		return 0, fmt.Errorf("error compare minhash signatures of different sizes: %d and %d", len(m), len(b))
+	}
+	if len(m) == 0 {
+		return 0, nil
+	}
+	same := 0
+	for i := range m {
+		if m[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(m)), nil
+}
+
+// LSHIndex is a locality-sensitive hashing index of MinHash signatures.
+// Signatures are split into bands, and two signatures become candidates
+// if they are identical in any band, so that similar sets are found
+// without comparing the query with every indexed signature.
+//
+// LSHIndex is not thread safe.
+type LSHIndex struct {
+	threshold float64
+	bands     int
+	rows      int
+	buckets   []map[uint64][]interface{}
+	sigs      map[interface{}]MinHash
+}
+
+// lshParams returns the number of bands and rows of signatures of the given
+// size, such that the similarity at which a pair becomes a candidate with
+// probability 1/2, about (1/bands)^(1/rows), is the closest to threshold.
+func lshParams(size int, threshold float64) (bands, rows int) {
+	best := math.Inf(1)
+	for r := 1; r <= size; r++ {
+		if size%r != 0 {
+			continue
+		}
+		b := size / r
+		if d := math.Abs(math.Pow(1/float64(b), 1/float64(r)) - threshold); d < best {
+			best, bands, rows = d, b, r
+		}
+	}
+	return bands, rows
+}
+
+func newLSHIndex(size int, threshold float64) *LSHIndex {
+	if size < 1 || threshold <= 0 || threshold > 1 {
+		panic(fmt.Sprintf("error create lsh index with signature size %d and threshold %v", size, threshold))
+	}
+	bands, rows := lshParams(size, threshold)
+	buckets := make([]map[uint64][]interface{}, bands)
+	for i := range buckets {
+		buckets[i] = make(map[uint64][]interface{})
+	}
+	return &LSHIndex{
+		threshold: threshold,
+		bands:     bands,
+		rows:      rows,
+		buckets:   buckets,
+		sigs:      make(map[interface{}]MinHash),
+	}
+}
+
+// Bands returns the number of bands the signatures are split into.
+func (x *LSHIndex) Bands() int {
+	return x.bands
+}
+
+// Len returns the number of indexed signatures.
+func (x *LSHIndex) Len() int {
+	return len(x.sigs)
+}
+
+func (x *LSHIndex) bandHash(sig MinHash, band int) uint64 {
+	h := fnvUint64(fnvOffset64, uint64(band))
+	for _, v := range sig[band*x.rows : (band+1)*x.rows] {
+		h = fnvUint64(h, v)
+	}
+	return h
+}
+
+func (x *LSHIndex) check(sig MinHash) error {
+	if len(sig) != x.bands*x.rows {
+		return fmt.Errorf("error use minhash signature of size %d with lsh index of size %d", len(sig), x.bands*x.rows)
+	}
+	return nil
+}
+
+// Insert indexes the signature of a set by the given key, which must be
+// hashable. An existing signature of the key is replaced.
+func (x *LSHIndex) Insert(key interface{}, sig MinHash) error {
+	if !hashable(key) {
+		return unhashableError(key, 0)
+	}
+	if err := x.check(sig); err != nil {
+		return err
+	}
+	x.Remove(key)
+	x.sigs[key] = sig
+	for i := 0; i < x.bands; i++ {
+		h := x.bandHash(sig, i)
+		x.buckets[i][h] = append(x.buckets[i][h], key)
+	}
+	return nil
+}
+
+// Remove removes the signature of the given key from the index.
+func (x *LSHIndex) Remove(key interface{}) {
+	if !hashable(key) {
+		return
+	}
+	sig, ok := x.sigs[key]
+	if !ok {
+		return
+	}
+	delete(x.sigs, key)
+	for i := 0; i < x.bands; i++ {
+		h := x.bandHash(sig, i)
+		keys := x.buckets[i][h]
+		for j := range keys {
+			if keys[j] == key {
+				keys = append(keys[:j], keys[j+1:]...)
+				break
+			}
+		}
+		if len(keys) == 0 {
+			delete(x.buckets[i], h)
+		} else {
+			x.buckets[i][h] = keys
+		}
+	}
+}
+
+// Query returns the keys of indexed sets whose estimated Jaccard index
+// with the given signature is not less than the threshold of the index,
+// in descending order of the similarity. Similar sets may be missed with
+// a small probability, which is lower for more similar sets.
+func (x *LSHIndex) Query(sig MinHash) ([]interface{}, error) {
+	if err := x.check(sig); err != nil {
+		return nil, err
+	}
+	similarity := make(map[interface{}]float64)
+	for i := 0; i < x.bands; i++ {
+		for _, key := range x.buckets[i][x.bandHash(sig, i)] {
+			if _, ok := similarity[key]; ok {
+				continue
+			}
+			similarity[key], _ = sig.Jaccard(x.sigs[key])
+		}
+	}
+	ret := []interface{}{}
+	for key, j := range similarity {
+		if j >= x.threshold {
+			ret = append(ret, key)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return similarity[ret[i]] > similarity[ret[j]]
+	})
+	return ret, nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func shingles(lo, hi int) Set {
+	s := NewSet()
+	for i := lo; i < hi; i++ {
+		s.Add(fmt.Sprintf("w%d", i)) //nolint:errcheck
+	}
+	return s
+}
+
+func Test_MinHash_Jaccard(t *testing.T) {
+	tests := []struct {
+		name string
+		a    Set
+		b    Set
+		want float64
+	}{
+		{"same", shingles(0, 100), shingles(0, 100), 1},
+		{"disjoint", shingles(0, 100), shingles(100, 200), 0},
+		{"half", shingles(0, 150), shingles(50, 200), 0.5},
+		{"mixed types", NewSet(1, 2, "a", 3.5), NewSet(1, "a"), 0.5},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMinHash(tt.a, 256).Jaccard(NewMinHash(tt.b, 256))
+			if err != nil {
+				t.Fatalf("MinHash.Jaccard() error = %v", err)
+			}
+			// 3 standard errors
+			if math.Abs(got-tt.want) > 3/math.Sqrt(256) {
+				t.Errorf("MinHash.Jaccard() = %v, want about %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := NewMinHash(NewSet(1), 8).Jaccard(NewMinHash(NewSet(1), 16)); err == nil {
+		t.Errorf("MinHash.Jaccard() of different sizes should return error")
+	}
+}
+
+func Test_lshParams(t *testing.T) {
+	tests := []struct {
+		size      int
+		threshold float64
+		bands     int
+		rows      int
+	}{
+		{128, 0.5, 32, 4},
+		{128, 0.8, 8, 16},
+		{100, 0.5, 20, 5},
+	}
+	for _, tt := range tests {
+		if b, r := lshParams(tt.size, tt.threshold); b != tt.bands || r != tt.rows {
+			t.Errorf("lshParams(%v, %v) = %v, %v, want %v, %v", tt.size, tt.threshold, b, r, tt.bands, tt.rows)
+		}
+	}
+}
+
+func Test_LSHIndex_Query(t *testing.T) {
+	x := NewLSHIndex(128, 0.5)
+	docs := map[string]Set{
+		"a":  shingles(0, 100),
+		"a2": shingles(10, 110),
+		"a3": shingles(40, 140),
+		"b":  shingles(1000, 1100),
+		"c":  shingles(2000, 2100),
+	}
+	for name, s := range docs {
+		if err := x.Insert(name, NewMinHash(s, 128)); err != nil {
+			t.Fatalf("LSHIndex.Insert() error = %v", err)
+		}
+	}
+	if x.Len() != 5 {
+		t.Errorf("LSHIndex.Len() = %v", x.Len())
+	}
+
+	got, err := x.Query(NewMinHash(shingles(0, 100), 128))
+	if err != nil {
+		t.Fatalf("LSHIndex.Query() error = %v", err)
+	}
+	want := []interface{}{"a", "a2"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("LSHIndex.Query() = %v, want %v", got, want)
+	}
+
+	x.Remove("a")
+	x.Remove("missing")
+	got, _ = x.Query(NewMinHash(shingles(0, 100), 128))
+	if fmt.Sprint(got) != "[a2]" || x.Len() != 4 {
+		t.Errorf("LSHIndex.Query() after Remove = %v", got)
+	}
+
+	if err := x.Insert("d", NewMinHash(shingles(0, 1), 64)); err == nil {
+		t.Errorf("LSHIndex.Insert() of wrong size should return error")
+	}
+	if err := x.Insert([]int{1}, NewMinHash(shingles(0, 1), 128)); err == nil {
+		t.Errorf("LSHIndex.Insert() unhashable key should return error")
+	}
+}
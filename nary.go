@@ -0,0 +1,150 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import "sort"
+
+// setView is the thread unsafe view of a set, see unsafeView.
+type setView struct {
+	length   int
+	contains func(interface{}) bool
+	foreach  func(func(int, interface{}) bool)
+}
+
+// viewsOf returns the views of the given sets, the callers must hold the
+// read locks of the sets.
+func viewsOf(sets []Set) []setView {
+	views := make([]setView, len(sets))
+	for i, s := range sets {
+		length, contains, foreach := unsafeView(s)
+		views[i] = setView{length(), contains, foreach}
+	}
+	return views
+}
+
+// newSetOr returns a new set of elems, or the result of fallback if a set
+// can not hold them, such as the elements of keyed sets.
+func newSetOr(elems []interface{}, fallback func() Set) Set {
+	ret := newSet()
+	if err := ret.Add(elems...); err != nil {
+		return fallback()
+	}
+	return ret
+}
+
+// UniteAll returns a new set containing the elements in any of the given
+// sets, it is empty if no set is given. The read locks of all thread safe
+// sets are held during the operation.
+// math formula: a ∪ b ∪ c ...
+func UniteAll(sets ...Set) Set {
+	defer rlockSets(sets...)()
+
+	var elems []interface{}
+	for _, v := range viewsOf(sets) {
+		v.foreach(func(_ int, elem interface{}) bool {
+			elems = append(elems, elem)
+			return true
+		})
+	}
+	return newSetOr(elems, func() Set {
+		// let the sets holding the elements compute the result
+		var ret Set = newSet()
+		for _, s := range sets {
+			ret = ret.Unite(s.ToThreadUnsafe())
+		}
+		return ret
+	})
+}
+
+// IntersectAll returns a new set containing the elements in all of the
+// given sets, it is empty if no set is given. The smallest set is iterated
+// and its elements are checked against the others from the smallest to the
+// largest one, nothing is visited if any of the sets is empty. The read
+// locks of all thread safe sets are held during the operation.
+// math formula: a ∩ b ∩ c ...
+func IntersectAll(sets ...Set) Set {
+	ret := newSet()
+	if len(sets) == 0 {
+		return ret
+	}
+	defer rlockSets(sets...)()
+
+	views := viewsOf(sets)
+	sort.SliceStable(views, func(i, j int) bool {
+		return views[i].length < views[j].length
+	})
+	if views[0].length == 0 {
+		return ret
+	}
+	others := views[1:]
+	var elems []interface{}
+	views[0].foreach(func(_ int, elem interface{}) bool {
+		for _, v := range others {
+			if !v.contains(elem) {
+				return true
+			}
+		}
+		elems = append(elems, elem)
+		return true
+	})
+	return newSetOr(elems, func() Set {
+		ret := sets[0].ToThreadUnsafe()
+		for _, s := range sets {
+			ret = ret.Intersect(s.ToThreadUnsafe())
+		}
+		return ret
+	})
+}
+
+// DiffAll returns a new set containing the elements in base but not in any
+// of the other sets. The elements of base are checked against the others
+// from the largest to the smallest one, and empty sets are skipped. The
+// read locks of all thread safe sets are held during the operation.
+// math formula: base - a - b ...
+func DiffAll(base Set, sets ...Set) Set {
+	ret := newSet()
+	defer rlockSets(append([]Set{base}, sets...)...)()
+
+	length, _, foreach := unsafeView(base)
+	if length() == 0 {
+		return ret
+	}
+	views := viewsOf(sets)
+	sort.SliceStable(views, func(i, j int) bool {
+		return views[i].length > views[j].length
+	})
+	for len(views) > 0 && views[len(views)-1].length == 0 {
+		views = views[:len(views)-1]
+	}
+	var elems []interface{}
+	foreach(func(_ int, elem interface{}) bool {
+		for _, v := range views {
+			if v.contains(elem) {
+				return true
+			}
+		}
+		elems = append(elems, elem)
+		return true
+	})
+	return newSetOr(elems, func() Set {
+		ret := base.ToThreadUnsafe().Diff(newSet())
+		for _, s := range sets {
+			ret = ret.Diff(s.ToThreadUnsafe())
+		}
+		return ret
+	})
+}
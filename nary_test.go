@@ -0,0 +1,184 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_UniteAll(t *testing.T) {
+	safe := NewSafeSet(1)
+	tests := []struct {
+		name string
+		sets []Set
+		want Set
+	}{
+		{"no sets", nil, NewSet()},
+		{"one set", []Set{NewSet(1, 2)}, NewSet(1, 2)},
+		{"mixed sets", []Set{NewSet(1, "a"), NewSafeSet(2, "a"), NewBitmapSet(3), NewSortedSet("b")}, NewSet(1, 2, 3, "a", "b")},
+		{"same set", []Set{safe, NewSet(2), safe}, NewSet(1, 2)},
+		{"empty sets", []Set{NewSet(), NewShardedSet(4)}, NewSet()},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UniteAll(tt.sets...); !got.Equal(tt.want) {
+				t.Errorf("UniteAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_IntersectAll(t *testing.T) {
+	safe := NewSafeSet(1, 2, 3)
+	tests := []struct {
+		name string
+		sets []Set
+		want Set
+	}{
+		{"no sets", nil, NewSet()},
+		{"one set", []Set{NewSet(1, 2)}, NewSet(1, 2)},
+		{"mixed sets", []Set{NewSet(1, 2, 3, "a"), safe, NewBitmapSet(2, 3, 4), NewShardedSet(4, 3, 2)}, NewSet(2, 3)},
+		{"same set", []Set{safe, safe, NewOrderedSet(3, 1)}, NewSet(1, 3)},
+		{"empty set", []Set{NewSet(1), NewSet()}, NewSet()},
+		{"disjoint sets", []Set{NewSet(1), NewSet(2), NewSet(1, 2)}, NewSet()},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IntersectAll(tt.sets...); !got.Equal(tt.want) {
+				t.Errorf("IntersectAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DiffAll(t *testing.T) {
+	safe := NewSafeSet(1, 2, 3, "a")
+	tests := []struct {
+		name string
+		base Set
+		sets []Set
+		want Set
+	}{
+		{"no sets", safe, nil, NewSet(1, 2, 3, "a")},
+		{"empty base", NewSet(), []Set{NewSet(1)}, NewSet()},
+		{"mixed sets", safe, []Set{NewBitmapSet(1), NewSet(), NewSortedSet("a")}, NewSet(2, 3)},
+		{"base itself", safe, []Set{NewSet(4), safe}, NewSet()},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffAll(tt.base, tt.sets...); !got.Equal(tt.want) {
+				t.Errorf("DiffAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_NAry_Unhashable(t *testing.T) {
+	u1, u2 := keyedUser{7, []string{"a"}}, keyedUser{8, nil}
+	keyed := NewKeyedSet(nil, 1, u1, u2)
+
+	if got := UniteAll(NewSet(1, 2), NewSafeSet(3), keyed); got.Len() != 5 || !got.ContainsAll(1, 2, 3, u1, u2) {
+		t.Errorf("UniteAll() = %v, want 5 elements", got)
+	}
+	if got := IntersectAll(keyed, NewKeyedSet(nil, u1, 1)); got.Len() != 2 || !got.ContainsAll(1, u1) {
+		t.Errorf("IntersectAll() = %v, want 2 elements", got)
+	}
+	if got := IntersectAll(keyed); got.Len() != 3 || got == keyed {
+		t.Errorf("IntersectAll() = %v, want a new set of 3 elements", got)
+	}
+	if got := DiffAll(keyed, NewSet(1), NewKeyedSet(nil, u2)); got.Len() != 1 || !got.Contains(u1) {
+		t.Errorf("DiffAll() = %v, want 1 element", got)
+	}
+	if keyed.Len() != 3 {
+		t.Errorf("the given set is changed to %v", keyed)
+	}
+}
+
+func Test_IntersectAll_Concurrent(t *testing.T) {
+	a := NewSafeSet(1, 2, 3)
+	b := NewShardedSet(4, 2, 3, 4)
+	c := NewSafeSet(3, 4, 5)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				IntersectAll(a, b, c)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				UniteAll(c, b, a)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				a.UniteWith(b)
+				c.Add(3)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := IntersectAll(a, b, c); !got.Equal(NewSet(3, 4)) {
+		t.Errorf("IntersectAll() = %v, want %v", got, NewSet(3, 4))
+	}
+}
+
+func benchmarkIntersectChain(b *testing.B, sets []Set) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ret := sets[0]
+		for _, s := range sets[1:] {
+			ret = ret.Intersect(s)
+		}
+	}
+}
+
+func benchmarkIntersectAll(b *testing.B, sets []Set) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		IntersectAll(sets...)
+	}
+}
+
+// naryBenchSets returns ten large sets and a tiny one at the end.
+func naryBenchSets() []Set {
+	sets := make([]Set, 0, 11)
+	for i := 0; i < 10; i++ {
+		s := newSet()
+		fill(s, 1000)
+		sets = append(sets, s)
+	}
+	return append(sets, NewSet(1, 2, 3))
+}
+
+func BenchmarkIntersectChain(b *testing.B) {
+	benchmarkIntersectChain(b, naryBenchSets())
+}
+
+func BenchmarkIntersectAll(b *testing.B) {
+	benchmarkIntersectAll(b, naryBenchSets())
+}
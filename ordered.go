@@ -0,0 +1,421 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import "container/list"
+
+type orderedSet struct {
+	elems *list.List
+	index map[interface{}]*list.Element
+}
+
+func newOrderedSet(elems ...interface{}) *orderedSet {
+	s := &orderedSet{
+		elems: list.New(),
+		index: make(map[interface{}]*list.Element),
+	}
+	err := s.Add(elems...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (s *orderedSet) Add(elems ...interface{}) error {
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		if _, ok := s.index[elem]; ok {
+			continue
+		}
+		s.index[elem] = s.elems.PushBack(elem)
+	}
+	return nil
+}
+
+func (s *orderedSet) Extend(b interface{}) error {
+	return extend(s, b)
+}
+
+func (s *orderedSet) Remove(elems ...interface{}) {
+	for _, elem := range elems {
+		if !hashable(elem) {
+			continue
+		}
+		if e, ok := s.index[elem]; ok {
+			s.elems.Remove(e)
+			delete(s.index, elem)
+		}
+	}
+}
+
+func (s *orderedSet) Contains(elem interface{}) bool {
+	ok, _ := s.ContainsErr(elem)
+	return ok
+}
+
+func (s *orderedSet) ContainsErr(elem interface{}) (bool, error) {
+	if !hashable(elem) {
+		return false, unhashableError(elem, 0)
+	}
+	_, ok := s.index[elem]
+	return ok, nil
+}
+
+func (s *orderedSet) ContainsAll(elems ...interface{}) bool {
+	for _, elem := range elems {
+		if !s.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *orderedSet) ContainsAny(elems ...interface{}) bool {
+	for _, elem := range elems {
+		if s.Contains(elem) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *orderedSet) Copy() Set {
+	c := newOrderedSet()
+	for e := s.elems.Front(); e != nil; e = e.Next() {
+		c.index[e.Value] = c.elems.PushBack(e.Value)
+	}
+	return c
+}
+
+func (s *orderedSet) Len() int {
+	return s.elems.Len()
+}
+
+func (s *orderedSet) String() string {
+	return formatSet(s)
+}
+
+func (s *orderedSet) Range(foreach func(index int, elem interface{}) bool) {
+	i := 0
+	for e := s.elems.Front(); e != nil; e = e.Next() {
+		if !foreach(i, e.Value) {
+			break
+		}
+		i++
+	}
+}
+
+func (s *orderedSet) Elements() []interface{} {
+	ret := make([]interface{}, 0, s.Len())
+	for e := s.elems.Front(); e != nil; e = e.Next() {
+		ret = append(ret, e.Value)
+	}
+	return ret
+}
+
+func (s *orderedSet) ToStrings() []string {
+	ret := []string{}
+	for e := s.elems.Front(); e != nil; e = e.Next() {
+		if str, ok := e.Value.(string); ok {
+			ret = append(ret, str)
+		}
+	}
+	return ret
+}
+
+func (s *orderedSet) ToInts() []int {
+	ret := []int{}
+	for e := s.elems.Front(); e != nil; e = e.Next() {
+		if i, ok := e.Value.(int); ok {
+			ret = append(ret, i)
+		}
+	}
+	return ret
+}
+
+func (s *orderedSet) ToThreadUnsafe() Set {
+	return s
+}
+
+func (s *orderedSet) ToThreadSafe() Set {
+	return &threadSafeOrderedSet{threadSafeSet{unsafe: s}}
+}
+
+func (s *orderedSet) Equal(b Set) bool {
+	b = b.ToThreadUnsafe()
+	if s.Len() != b.Len() {
+		return false
+	}
+	return s.isSubsetOf(b)
+}
+
+func (s *orderedSet) IsSubsetOf(b Set) bool {
+	b = b.ToThreadUnsafe()
+	if s.Len() > b.Len() {
+		return false
+	}
+	return s.isSubsetOf(b)
+}
+
+func (s *orderedSet) IsSupersetOf(b Set) bool {
+	b = b.ToThreadUnsafe()
+	if b.Len() > s.Len() {
+		return false
+	}
+	ret := true
+	b.Range(func(_ int, elem interface{}) bool {
+		ret = s.Contains(elem)
+		return ret
+	})
+	return ret
+}
+
+func (s *orderedSet) isSubsetOf(b Set) bool {
+	for e := s.elems.Front(); e != nil; e = e.Next() {
+		if !b.Contains(e.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// filter returns a new ordered set containing the elements in s for
+// which keep returns true, in the order of s.
+func (s *orderedSet) filter(keep func(elem interface{}) bool) *orderedSet {
+	ret := newOrderedSet()
+	for e := s.elems.Front(); e != nil; e = e.Next() {
+		if keep(e.Value) {
+			ret.index[e.Value] = ret.elems.PushBack(e.Value)
+		}
+	}
+	return ret
+}
+
+func (s *orderedSet) Diff(b Set) Set {
+	b = b.ToThreadUnsafe()
+	return s.filter(func(elem interface{}) bool {
+		return !b.Contains(elem)
+	})
+}
+
+// SymmetricDiff returns a new ordered set, unless b holds elements which
+// are not hashable, then the result is computed like set.SymmetricDiff.
+func (s *orderedSet) SymmetricDiff(b Set) Set {
+	b = b.ToThreadUnsafe()
+	if checkSetHashable(b) != nil {
+		return newSet(s.Elements()...).SymmetricDiff(b)
+	}
+	diff := s.filter(func(elem interface{}) bool {
+		return !b.Contains(elem)
+	})
+	b.Range(func(_ int, elem interface{}) bool {
+		if !s.Contains(elem) {
+			diff.index[elem] = diff.elems.PushBack(elem)
+		}
+		return true
+	})
+	return diff
+}
+
+// Unite returns a new ordered set, unless b holds elements which are not
+// hashable, then the result is computed like set.Unite.
+func (s *orderedSet) Unite(b Set) Set {
+	b = b.ToThreadUnsafe()
+	if checkSetHashable(b) != nil {
+		return newSet(s.Elements()...).Unite(b)
+	}
+	union := s.Copy().(*orderedSet)
+	union.UniteWith(b) //nolint:errcheck
+	return union
+}
+
+func (s *orderedSet) Intersect(b Set) Set {
+	b = b.ToThreadUnsafe()
+	return s.filter(b.Contains)
+}
+
+func (s *orderedSet) UniteWith(b Set) error {
+	b = b.ToThreadUnsafe()
+	if err := checkSetHashable(b); err != nil {
+		return err
+	}
+	b.Range(func(_ int, elem interface{}) bool {
+		if _, ok := s.index[elem]; !ok {
+			s.index[elem] = s.elems.PushBack(elem)
+		}
+		return true
+	})
+	return nil
+}
+
+// removeIf removes the elements for which remove returns true.
+func (s *orderedSet) removeIf(remove func(elem interface{}) bool) {
+	for e := s.elems.Front(); e != nil; {
+		next := e.Next()
+		if remove(e.Value) {
+			s.elems.Remove(e)
+			delete(s.index, e.Value)
+		}
+		e = next
+	}
+}
+
+func (s *orderedSet) IntersectWith(b Set) {
+	b = b.ToThreadUnsafe()
+	s.removeIf(func(elem interface{}) bool {
+		return !b.Contains(elem)
+	})
+}
+
+func (s *orderedSet) DiffWith(b Set) {
+	b = b.ToThreadUnsafe()
+	s.removeIf(b.Contains)
+}
+
+func (s *orderedSet) SymmetricDiffWith(b Set) error {
+	b = b.ToThreadUnsafe()
+	if b == Set(s) {
+		s.elems.Init()
+		s.index = make(map[interface{}]*list.Element)
+		return nil
+	}
+	if err := checkSetHashable(b); err != nil {
+		return err
+	}
+	b.Range(func(_ int, elem interface{}) bool {
+		if e, ok := s.index[elem]; ok {
+			s.elems.Remove(e)
+			delete(s.index, elem)
+		} else {
+			s.index[elem] = s.elems.PushBack(elem)
+		}
+		return true
+	})
+	return nil
+}
+
+func (s *orderedSet) First() (interface{}, bool) {
+	e := s.elems.Front()
+	if e == nil {
+		return nil, false
+	}
+	return e.Value, true
+}
+
+func (s *orderedSet) Last() (interface{}, bool) {
+	e := s.elems.Back()
+	if e == nil {
+		return nil, false
+	}
+	return e.Value, true
+}
+
+func (s *orderedSet) IndexOf(elem interface{}) int {
+	if !s.Contains(elem) {
+		return -1
+	}
+	i := 0
+	for e := s.elems.Front(); e != nil; e = e.Next() {
+		if e == s.index[elem] {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+func (s *orderedSet) MoveToEnd(elem interface{}) bool {
+	if !s.Contains(elem) {
+		return false
+	}
+	s.elems.MoveToBack(s.index[elem])
+	return true
+}
+
+// MarshalJSON implements json.Marshaler. Sets containing only ints or only
+// strings are encoded as plain JSON arrays in insertion order, the others
+// are encoded in the type-tagged form, which keeps the insertion order
+// among the elements of the same tag.
+func (s *orderedSet) MarshalJSON() ([]byte, error) {
+	return marshalElements(s.Elements())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces all elements in
+// the set with the decoded ones.
+func (s *orderedSet) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalElements(data)
+	if err != nil {
+		return err
+	}
+	if elems == nil {
+		return nil
+	}
+	ret := newOrderedSet()
+	if err := ret.Add(elems...); err != nil {
+		return err
+	}
+	*s = *ret
+	return nil
+}
+
+type threadSafeOrderedSet struct {
+	threadSafeSet
+}
+
+func newThreadSafeOrderedSet(elems ...interface{}) *threadSafeOrderedSet {
+	return &threadSafeOrderedSet{threadSafeSet{unsafe: newOrderedSet(elems...)}}
+}
+
+func (s *threadSafeOrderedSet) ordered() *orderedSet {
+	return s.unsafe.(*orderedSet)
+}
+
+func (s *threadSafeOrderedSet) Copy() Set {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &threadSafeOrderedSet{threadSafeSet{unsafe: s.unsafe.Copy()}}
+}
+
+func (s *threadSafeOrderedSet) ToThreadSafe() Set {
+	return s
+}
+
+func (s *threadSafeOrderedSet) First() (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ordered().First()
+}
+
+func (s *threadSafeOrderedSet) Last() (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ordered().Last()
+}
+
+func (s *threadSafeOrderedSet) IndexOf(elem interface{}) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ordered().IndexOf(elem)
+}
+
+func (s *threadSafeOrderedSet) MoveToEnd(elem interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ordered().MoveToEnd(elem)
+}
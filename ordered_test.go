@@ -0,0 +1,238 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func Test_orderedSet_Add(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       OrderedSet
+		elems   []interface{}
+		want    []interface{}
+		wantErr bool
+	}{
+		{"", NewOrderedSet(), []interface{}{3, "1", 2, 1.5}, []interface{}{3, "1", 2, 1.5}, false},
+		{"dup", NewOrderedSet(2, 1), []interface{}{3, 1, 2}, []interface{}{2, 1, 3}, false},
+		{"unhashable", NewOrderedSet(), []interface{}{[]int{1}}, []interface{}{}, true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.s.Add(tt.elems...); (err != nil) != tt.wantErr {
+				t.Errorf("orderedSet.Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := tt.s.Elements(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orderedSet.Elements() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_orderedSet_Remove(t *testing.T) {
+	s := NewOrderedSet(1, 2, "3", 4)
+	s.Remove(2, 5)
+	if got, want := s.Elements(), []interface{}{1, "3", 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("orderedSet.Remove() = %v, want %v", got, want)
+	}
+	s.Add(2)
+	if got, want := s.Elements(), []interface{}{1, "3", 4, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("orderedSet.Add() = %v, want %v", got, want)
+	}
+}
+
+func Test_orderedSet_Positions(t *testing.T) {
+	s := NewOrderedSet("c", "a", "b")
+	if got, ok := s.First(); !ok || got != "c" {
+		t.Errorf("orderedSet.First() = %v, %v, want c", got, ok)
+	}
+	if got, ok := s.Last(); !ok || got != "b" {
+		t.Errorf("orderedSet.Last() = %v, %v, want b", got, ok)
+	}
+	if got := s.IndexOf("a"); got != 1 {
+		t.Errorf("orderedSet.IndexOf() = %v, want 1", got)
+	}
+	if got := s.IndexOf("d"); got != -1 {
+		t.Errorf("orderedSet.IndexOf() = %v, want -1", got)
+	}
+	if !s.MoveToEnd("c") || s.MoveToEnd("d") {
+		t.Errorf("orderedSet.MoveToEnd() returns wrong result")
+	}
+	if got, want := s.ToStrings(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("orderedSet.ToStrings() = %v, want %v", got, want)
+	}
+
+	empty := NewOrderedSet()
+	if _, ok := empty.First(); ok {
+		t.Errorf("orderedSet.First() of empty set returns true")
+	}
+	if _, ok := empty.Last(); ok {
+		t.Errorf("orderedSet.Last() of empty set returns true")
+	}
+}
+
+func Test_orderedSet_Operations(t *testing.T) {
+	tests := []struct {
+		name      string
+		a         OrderedSet
+		b         Set
+		diff      []interface{}
+		symmetric []interface{}
+		union     []interface{}
+		intersect []interface{}
+	}{
+		{
+			"ordered",
+			NewOrderedSet(4, 3, 2, 1),
+			NewOrderedSet(5, 1, 3),
+			[]interface{}{4, 2},
+			[]interface{}{4, 2, 5},
+			[]interface{}{4, 3, 2, 1, 5},
+			[]interface{}{3, 1},
+		},
+		{
+			"unordered",
+			NewOrderedSet("b", "a", 1),
+			NewSafeSet(1),
+			[]interface{}{"b", "a"},
+			[]interface{}{"b", "a"},
+			[]interface{}{"b", "a", 1},
+			[]interface{}{1},
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Diff(tt.b).Elements(); !reflect.DeepEqual(got, tt.diff) {
+				t.Errorf("orderedSet.Diff() = %v, want %v", got, tt.diff)
+			}
+			if got := tt.a.SymmetricDiff(tt.b).Elements(); !reflect.DeepEqual(got, tt.symmetric) {
+				t.Errorf("orderedSet.SymmetricDiff() = %v, want %v", got, tt.symmetric)
+			}
+			if got := tt.a.Unite(tt.b).Elements(); !reflect.DeepEqual(got, tt.union) {
+				t.Errorf("orderedSet.Unite() = %v, want %v", got, tt.union)
+			}
+			if got := tt.a.Intersect(tt.b).Elements(); !reflect.DeepEqual(got, tt.intersect) {
+				t.Errorf("orderedSet.Intersect() = %v, want %v", got, tt.intersect)
+			}
+		})
+	}
+
+	// elements which are not hashable are kept in an unordered result
+	u := keyedUser{7, []string{"a"}}
+	keyed := NewKeyedSet(nil, 1, u)
+	if got := NewOrderedSet(1, 2).Unite(keyed); got.Len() != 3 || !got.ContainsAll(1, 2, u) {
+		t.Errorf("orderedSet.Unite() with keyed set = %v", got)
+	}
+	if got := NewSafeOrderedSet(1, 2).SymmetricDiff(keyed); got.Len() != 2 || !got.ContainsAll(2, u) {
+		t.Errorf("orderedSet.SymmetricDiff() with keyed set = %v", got)
+	}
+}
+
+func Test_orderedSet_Compare(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        Set
+		b        Set
+		equal    bool
+		subset   bool
+		superset bool
+	}{
+		{"", NewOrderedSet(1, 2, 3), NewOrderedSet(3, 2, 1), true, true, true},
+		{"", NewOrderedSet(1, 2), NewSet(1, 2, 3), false, true, false},
+		{"", NewSet(1, 2, 3), NewOrderedSet(1, 2), false, false, true},
+		{"", NewSafeSet(1, 2), NewSafeOrderedSet(2, 1), true, true, true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.equal {
+				t.Errorf("Equal() = %v, want %v", got, tt.equal)
+			}
+			if got := tt.a.IsSubsetOf(tt.b); got != tt.subset {
+				t.Errorf("IsSubsetOf() = %v, want %v", got, tt.subset)
+			}
+			if got := tt.a.IsSupersetOf(tt.b); got != tt.superset {
+				t.Errorf("IsSupersetOf() = %v, want %v", got, tt.superset)
+			}
+		})
+	}
+}
+
+func Test_orderedSet_Copy_And_String(t *testing.T) {
+	s := NewOrderedSetFrom([]int{3, 1, 2})
+	c := s.Copy().(OrderedSet)
+	c.Add(0)
+	if s.Contains(0) {
+		t.Errorf("orderedSet.Copy() shares storage with the origin set")
+	}
+	if got, want := c.String(), "Set[3 1 2 0]"; got != want {
+		t.Errorf("orderedSet.String() = %v, want %v", got, want)
+	}
+}
+
+func Test_orderedSet_JSON(t *testing.T) {
+	s := NewSafeOrderedSet(3, 1, 2)
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != "[3,1,2]" {
+		t.Errorf("json.Marshal() = %s, want [3,1,2]", data)
+	}
+
+	got := NewOrderedSet()
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Elements(), s.Elements()) {
+		t.Errorf("json.Unmarshal() = %v, want %v", got, s)
+	}
+}
+
+func Test_threadSafeOrderedSet(t *testing.T) {
+	s := NewSafeOrderedSet()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Add(i)
+			s.MoveToEnd(i)
+			s.IndexOf(i)
+		}(i)
+	}
+	wg.Wait()
+
+	if s.Len() != 100 {
+		t.Errorf("threadSafeOrderedSet.Len() = %v, want 100", s.Len())
+	}
+	if _, ok := s.Copy().(*threadSafeOrderedSet); !ok {
+		t.Errorf("threadSafeOrderedSet.Copy() = %T, want *threadSafeOrderedSet", s.Copy())
+	}
+	if s.ToThreadSafe() != s {
+		t.Errorf("threadSafeOrderedSet.ToThreadSafe() returns a different set")
+	}
+	if _, ok := s.ToThreadUnsafe().(*orderedSet); !ok {
+		t.Errorf("threadSafeOrderedSet.ToThreadUnsafe() = %T, want *orderedSet", s.ToThreadUnsafe())
+	}
+}
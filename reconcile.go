@@ -0,0 +1,387 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const reconcileVersion byte = 1
+
+// messages of the reconciliation protocol.
+const (
+	// initiator: version, set length and the sketch
+	msgSketch byte = iota + 1
+	// responder: set length, the sketch is too small to decode
+	msgRetry
+	// responder: the wanted keys and the elements only in its set
+	msgResult
+	// initiator: the elements wanted by the responder
+	msgElements
+	// either: the reason to abort
+	msgAbort
+)
+
+const (
+	// reconcileInitialCells is the number of cells of the first sketch,
+	// which is enough for about 20 different elements.
+	reconcileInitialCells = 48
+	// maxReconcileMessage is the maximum size of a message.
+	maxReconcileMessage = 1 << 30
+)
+
+// ErrReconcile is returned if the reconciliation fails because of the
+// remote peer, use errors.Is to check it.
+var ErrReconcile = errors.New("goset: reconciliation failed")
+
+// Reconciliation is the result of Reconcile and ServeReconcile.
+type Reconciliation struct {
+	// Missing are the elements only in the remote set.
+	Missing Set
+	// Extra are the elements only in the local set.
+	Extra Set
+	// Rounds is the number of sketches sent by the initiator.
+	Rounds int
+	// BytesSent and BytesReceived are the numbers of bytes transferred.
+	BytesSent     int
+	BytesReceived int
+}
+
+// SymmetricDiff returns the symmetric difference between the two sets.
+func (r *Reconciliation) SymmetricDiff() Set {
+	return UniteAll(r.Missing, r.Extra)
+}
+
+// maxReconcileCells returns the maximum number of cells of sketches for
+// the local set of length a and the remote set of length b, which is large
+// enough to decode any difference between them. b is told by the remote
+// peer, so it is clamped before the arithmetic, and the result never
+// exceeds the cells fitting in a message.
+func maxReconcileCells(a int, b uint64) int {
+	const limit = maxReconcileMessage / ibltMinCellSize
+	if b > limit {
+		b = limit
+	}
+	n := 4*(uint64(a)+b) + 2*reconcileInitialCells
+	if n > limit {
+		n = limit
+	}
+	return int(n)
+}
+
+type reconcilePeer struct {
+	rw    io.ReadWriter
+	elems []interface{}
+	keys  []uint64
+	ret   *Reconciliation
+}
+
+func newReconcilePeer(rw io.ReadWriter, s Set) (*reconcilePeer, error) {
+	p := &reconcilePeer{rw: rw, ret: &Reconciliation{}}
+	// the snapshot of a thread safe set
+	p.elems = s.Elements()
+	p.keys = make([]uint64, len(p.elems))
+	for i, elem := range p.elems {
+		if typedAssert(elem) == typedAny {
+			return p, fmt.Errorf("goset: can not reconcile %v of type %T, only int and string are supported", elem, elem)
+		}
+		p.keys[i] = hashElem(elem)
+	}
+	return p, nil
+}
+
+func (p *reconcilePeer) sketch(cells int) *iblt {
+	t := newIBLT(cells)
+	for _, key := range p.keys {
+		t.insert(key)
+	}
+	return t
+}
+
+// elemsOf returns the local elements of the keys.
+func (p *reconcilePeer) elemsOf(keys []uint64) []interface{} {
+	wanted := make(map[uint64]struct{}, len(keys))
+	for _, key := range keys {
+		wanted[key] = struct{}{}
+	}
+	ret := make([]interface{}, 0, len(keys))
+	for i, key := range p.keys {
+		if _, ok := wanted[key]; ok {
+			ret = append(ret, p.elems[i])
+		}
+	}
+	return ret
+}
+
+func (p *reconcilePeer) send(msg []byte) error {
+	buf := make([]byte, 4+len(msg))
+	binary.BigEndian.PutUint32(buf, uint32(len(msg)))
+	copy(buf[4:], msg)
+	n, err := p.rw.Write(buf)
+	p.ret.BytesSent += n
+	return err
+}
+
+// recv reads a message exactly, so that nothing after the protocol is
+// consumed from rw.
+func (p *reconcilePeer) recv() ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(p.rw, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n == 0 || n > maxReconcileMessage {
+		return nil, fmt.Errorf("%w: invalid message size %d", ErrReconcile, n)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(p.rw, msg); err != nil {
+		return nil, err
+	}
+	p.ret.BytesReceived += 4 + int(n)
+	if msg[0] == msgAbort {
+		return nil, fmt.Errorf("%w: aborted by remote: %s", ErrReconcile, msg[1:])
+	}
+	return msg, nil
+}
+
+// abort tells the remote peer the reason and returns err.
+func (p *reconcilePeer) abort(err error) error {
+	p.send(append([]byte{msgAbort}, err.Error()...)) //nolint:errcheck
+	return err
+}
+
+func unexpectedMessage(msg []byte, want byte) error {
+	return fmt.Errorf("%w: unexpected message %d, want %d", ErrReconcile, msg[0], want)
+}
+
+// Reconcile computes the symmetric difference between the local set and
+// the set of the remote peer running ServeReconcile on the other end of
+// rw, transferring data proportional to the size of the difference
+// instead of the sets. Both ends get the elements only in the remote set
+// and the ones only in the local set, so they can converge by uniting the
+// missing elements.
+//
+// The peers exchange invertible Bloom lookup tables of the hashes of their
+// elements, which are doubled until the difference can be decoded, and
+// then the elements of the different hashes. Only sets of ints and strings
+// can be reconciled, which are hashed stably across processes. The local
+// set is snapshotted by Elements at the beginning.
+func Reconcile(rw io.ReadWriter, s Set) (*Reconciliation, error) {
+	p, err := newReconcilePeer(rw, s)
+	if err != nil {
+		return nil, p.abort(err)
+	}
+
+	var tmp [binary.MaxVarintLen64]byte
+	for cells := reconcileInitialCells; ; cells *= 2 {
+		msg := []byte{msgSketch, reconcileVersion}
+		msg = append(msg, tmp[:binary.PutUvarint(tmp[:], uint64(len(p.elems)))]...)
+		msg = appendIBLT(msg, p.sketch(cells))
+		if err := p.send(msg); err != nil {
+			return nil, err
+		}
+		p.ret.Rounds++
+
+		reply, err := p.recv()
+		if err != nil {
+			return nil, err
+		}
+		switch reply[0] {
+		case msgRetry:
+			remoteLen, size := binary.Uvarint(reply[1:])
+			if size <= 0 {
+				return nil, p.abort(fmt.Errorf("%w: invalid retry message", ErrReconcile))
+			}
+			if cells*2 > maxReconcileCells(len(p.elems), remoteLen) {
+				return nil, p.abort(fmt.Errorf("%w: can not decode the difference with %d cells", ErrReconcile, cells))
+			}
+			continue
+		case msgResult:
+		default:
+			return nil, p.abort(unexpectedMessage(reply, msgResult))
+		}
+
+		wanted, rest, err := readKeys(reply[1:])
+		if err != nil {
+			return nil, p.abort(err)
+		}
+		missing, _, err := readReconcileElements(rest)
+		if err != nil {
+			return nil, p.abort(err)
+		}
+		extra := p.elemsOf(wanted)
+		if err := p.send(appendReconcileElements([]byte{msgElements}, extra)); err != nil {
+			return nil, err
+		}
+		p.ret.Missing = newSet(missing...)
+		p.ret.Extra = newSet(extra...)
+		return p.ret, nil
+	}
+}
+
+// ServeReconcile runs the other end of Reconcile, see Reconcile for
+// details.
+func ServeReconcile(rw io.ReadWriter, s Set) (*Reconciliation, error) {
+	p, err := newReconcilePeer(rw, s)
+	if err != nil {
+		// abort after the first sketch, since writes may block until the
+		// remote reads, like net.Pipe
+		if _, rerr := p.recv(); rerr != nil {
+			return nil, err
+		}
+		return nil, p.abort(err)
+	}
+
+	var tmp [binary.MaxVarintLen64]byte
+	for {
+		msg, err := p.recv()
+		if err != nil {
+			return nil, err
+		}
+		if msg[0] != msgSketch {
+			return nil, p.abort(unexpectedMessage(msg, msgSketch))
+		}
+		if len(msg) < 2 || msg[1] != reconcileVersion {
+			return nil, p.abort(fmt.Errorf("%w: unsupported version", ErrReconcile))
+		}
+		remoteLen, size := binary.Uvarint(msg[2:])
+		if size <= 0 {
+			return nil, p.abort(fmt.Errorf("%w: invalid sketch message", ErrReconcile))
+		}
+		remote, _, err := readIBLT(msg[2+size:], maxReconcileCells(len(p.elems), remoteLen))
+		if err != nil {
+			return nil, p.abort(fmt.Errorf("%w: %v", ErrReconcile, err))
+		}
+		p.ret.Rounds++
+
+		local := p.sketch(len(remote.cells))
+		local.subtract(remote)
+		extraKeys, wanted, ok := local.decode()
+		if !ok {
+			retry := append([]byte{msgRetry}, tmp[:binary.PutUvarint(tmp[:], uint64(len(p.elems)))]...)
+			if err := p.send(retry); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		extra := p.elemsOf(extraKeys)
+		reply := appendKeys([]byte{msgResult}, wanted)
+		reply = appendReconcileElements(reply, extra)
+		if err := p.send(reply); err != nil {
+			return nil, err
+		}
+		msg, err = p.recv()
+		if err != nil {
+			return nil, err
+		}
+		if msg[0] != msgElements {
+			return nil, p.abort(unexpectedMessage(msg, msgElements))
+		}
+		missing, _, err := readReconcileElements(msg[1:])
+		if err != nil {
+			return nil, err
+		}
+		p.ret.Missing = newSet(missing...)
+		p.ret.Extra = newSet(extra...)
+		return p.ret, nil
+	}
+}
+
+var errInvalidReconcileMessage = fmt.Errorf("%w: invalid message", ErrReconcile)
+
+func appendKeys(buf []byte, keys []uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	buf = append(buf, tmp[:binary.PutUvarint(tmp[:], uint64(len(keys)))]...)
+	for _, key := range keys {
+		binary.BigEndian.PutUint64(tmp[:8], key)
+		buf = append(buf, tmp[:8]...)
+	}
+	return buf
+}
+
+func readKeys(data []byte) ([]uint64, []byte, error) {
+	n, size := binary.Uvarint(data)
+	if size <= 0 || n > uint64(len(data)-size)/8 {
+		return nil, nil, errInvalidReconcileMessage
+	}
+	data = data[size:]
+	keys := make([]uint64, n)
+	for i := range keys {
+		keys[i] = binary.BigEndian.Uint64(data)
+		data = data[8:]
+	}
+	return keys, data, nil
+}
+
+// appendReconcileElements appends the ints and strings in elems to buf,
+// each of them is tagged by its type.
+func appendReconcileElements(buf []byte, elems []interface{}) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	buf = append(buf, tmp[:binary.PutUvarint(tmp[:], uint64(len(elems)))]...)
+	for _, elem := range elems {
+		switch e := elem.(type) {
+		case int:
+			buf = append(buf, byte(typedInt))
+			buf = append(buf, tmp[:binary.PutVarint(tmp[:], int64(e))]...)
+		case string:
+			buf = append(buf, byte(typedString))
+			buf = append(buf, tmp[:binary.PutUvarint(tmp[:], uint64(len(e)))]...)
+			buf = append(buf, e...)
+		}
+	}
+	return buf
+}
+
+func readReconcileElements(data []byte) ([]interface{}, []byte, error) {
+	n, size := binary.Uvarint(data)
+	// each element takes at least 2 bytes
+	if size <= 0 || n > uint64(len(data)-size)/2 {
+		return nil, nil, errInvalidReconcileMessage
+	}
+	data = data[size:]
+	elems := make([]interface{}, 0, n)
+	for i := uint64(0); i < n; i++ {
+		if len(data) == 0 {
+			return nil, nil, errInvalidReconcileMessage
+		}
+		tag := typed(data[0])
+		data = data[1:]
+		switch tag {
+		case typedInt:
+			v, size := binary.Varint(data)
+			if size <= 0 {
+				return nil, nil, errInvalidReconcileMessage
+			}
+			elems = append(elems, int(v))
+			data = data[size:]
+		case typedString:
+			l, size := binary.Uvarint(data)
+			if size <= 0 || l > uint64(len(data)-size) {
+				return nil, nil, errInvalidReconcileMessage
+			}
+			elems = append(elems, string(data[size:size+int(l)]))
+			data = data[size+int(l):]
+		default:
+			return nil, nil, errInvalidReconcileMessage
+		}
+	}
+	return elems, data, nil
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"testing"
+)
+
+// reconcileOverPipe runs Reconcile with a and ServeReconcile with b over
+// net.Pipe.
+func reconcileOverPipe(a, b Set) (ra, rb *Reconciliation, erra, errb error) {
+	x, y := net.Pipe()
+	defer x.Close()
+	defer y.Close()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		rb, errb = ServeReconcile(y, b)
+	}()
+	ra, erra = Reconcile(x, a)
+	<-done
+	return ra, rb, erra, errb
+}
+
+func Test_Reconcile(t *testing.T) {
+	large := NewSetFromInts(rangeInts(0, 10000))
+	largeDiff := large.Copy()
+	largeDiff.Remove(1, 2, 3, 4, 5)
+	largeDiff.Add(-1, -2, "x", "y", "z") //nolint:errcheck
+	manyDiff := NewSetFromInts(rangeInts(0, 1000))
+	manyDiff.Add("only") //nolint:errcheck
+
+	tests := []struct {
+		name       string
+		a, b       Set
+		wantRounds int
+	}{
+		{"empty sets", NewSet(), NewSet(), 1},
+		{"same sets", NewSet(1, "a"), NewSafeSet("a", 1), 1},
+		{"empty remote", NewSet(1, 2, "a"), NewSet(), 1},
+		{"empty local", NewSet(), NewSortedSet(1, 2, "a"), 1},
+		{"small difference", NewSet(1, 2, 3, "a"), NewSet(2, 3, 4, "b"), 1},
+		{"large sets", large, largeDiff, 1},
+		{"many differences", manyDiff, NewSet("only"), 6},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			ra, rb, erra, errb := reconcileOverPipe(tt.a, tt.b)
+			if erra != nil || errb != nil {
+				t.Fatalf("Reconcile() error = %v, %v", erra, errb)
+			}
+			wantMissing, wantExtra := tt.b.Diff(tt.a), tt.a.Diff(tt.b)
+			if !ra.Missing.Equal(wantMissing) || !ra.Extra.Equal(wantExtra) {
+				t.Errorf("Reconcile() = missing %v, extra %v, want %v, %v", ra.Missing, ra.Extra, wantMissing, wantExtra)
+			}
+			if !rb.Missing.Equal(wantExtra) || !rb.Extra.Equal(wantMissing) {
+				t.Errorf("ServeReconcile() = missing %v, extra %v, want %v, %v", rb.Missing, rb.Extra, wantExtra, wantMissing)
+			}
+			if !ra.SymmetricDiff().Equal(tt.a.SymmetricDiff(tt.b)) {
+				t.Errorf("SymmetricDiff() = %v", ra.SymmetricDiff())
+			}
+			if ra.Rounds != tt.wantRounds || rb.Rounds != tt.wantRounds {
+				t.Errorf("Rounds = %v and %v, want %v", ra.Rounds, rb.Rounds, tt.wantRounds)
+			}
+			if ra.BytesSent != rb.BytesReceived || ra.BytesReceived != rb.BytesSent {
+				t.Errorf("bytes sent and received mismatch: %+v, %+v", ra, rb)
+			}
+		})
+	}
+}
+
+func Test_Reconcile_Bandwidth(t *testing.T) {
+	a := NewSet()
+	for i := 0; i < 100000; i++ {
+		a.Add("host-" + strconv.Itoa(i)) //nolint:errcheck
+	}
+	b := a.Copy()
+	b.Remove("host-1", "host-2", "host-3")
+	b.Add("host-new-1", "host-new-2") //nolint:errcheck
+
+	ra, rb, erra, errb := reconcileOverPipe(a, b)
+	if erra != nil || errb != nil {
+		t.Fatalf("Reconcile() error = %v, %v", erra, errb)
+	}
+	if ra.Missing.Len() != 2 || rb.Missing.Len() != 3 {
+		t.Fatalf("Reconcile() = %v, %v", ra.Missing, rb.Missing)
+	}
+	// the elements take about 1MB
+	if total := ra.BytesSent + ra.BytesReceived; total > 2048 {
+		t.Errorf("Reconcile() transfers %d bytes for 5 different elements", total)
+	}
+
+	// both ends converge
+	a.UniteWith(ra.Missing) //nolint:errcheck
+	b.UniteWith(rb.Missing) //nolint:errcheck
+	if !a.Equal(b) {
+		t.Errorf("sets do not converge")
+	}
+}
+
+func Test_Reconcile_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Set
+	}{
+		{"unsupported local element", NewSet(1.5), NewSet(1)},
+		{"unsupported remote element", NewSet(1), NewSet(1.5)},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, erra, errb := reconcileOverPipe(tt.a, tt.b)
+			if erra == nil || errb == nil {
+				t.Fatalf("Reconcile() error = %v, %v, want errors at both ends", erra, errb)
+			}
+			// the end without the unsupported element knows it by the abort
+			if !errors.Is(erra, ErrReconcile) && !errors.Is(errb, ErrReconcile) {
+				t.Errorf("Reconcile() error = %v, %v, want ErrReconcile", erra, errb)
+			}
+		})
+	}
+
+	// garbage from the remote peer
+	x, y := net.Pipe()
+	defer x.Close()
+	go func() {
+		y.Write([]byte{0, 0, 0, 1, msgElements}) //nolint:errcheck
+		io.Copy(io.Discard, y)                   //nolint:errcheck
+	}()
+	if _, err := ServeReconcile(x, NewSet(1)); !errors.Is(err, ErrReconcile) {
+		t.Errorf("ServeReconcile() error = %v, want ErrReconcile", err)
+	}
+	y.Close()
+}
+
+func Test_ServeReconcile_Hostile(t *testing.T) {
+	// a small sketch claiming a huge remote set and table
+	var tmp [binary.MaxVarintLen64]byte
+	msg := []byte{msgSketch, reconcileVersion}
+	msg = append(msg, tmp[:binary.PutUvarint(tmp[:], 1<<40)]...)
+	msg = append(msg, tmp[:binary.PutUvarint(tmp[:], 3<<38)]...)
+	msg = append(msg, make([]byte, 8)...)
+	frame := make([]byte, 4, 4+len(msg))
+	binary.BigEndian.PutUint32(frame, uint32(len(msg)))
+	frame = append(frame, msg...)
+
+	x, y := net.Pipe()
+	defer x.Close()
+	go func() {
+		y.Write(frame)         //nolint:errcheck
+		io.Copy(io.Discard, y) //nolint:errcheck
+	}()
+	if _, err := ServeReconcile(x, NewSet(1, 2, 3)); !errors.Is(err, ErrReconcile) {
+		t.Errorf("ServeReconcile() error = %v, want ErrReconcile", err)
+	}
+	y.Close()
+}
+
+func Test_maxReconcileCells(t *testing.T) {
+	const limit = maxReconcileMessage / ibltMinCellSize
+	tests := []struct {
+		name string
+		a    int
+		b    uint64
+		want int
+	}{
+		{"empty sets", 0, 0, 2 * reconcileInitialCells},
+		{"small sets", 10, 20, 120 + 2*reconcileInitialCells},
+		{"huge remote set", 10, 1 << 40, limit},
+		{"overflowing remote set", 10, math.MaxUint64, limit},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maxReconcileCells(tt.a, tt.b); got != tt.want {
+				t.Errorf("maxReconcileCells() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Reconcile_KeepsStream(t *testing.T) {
+	x, y := net.Pipe()
+	defer x.Close()
+	defer y.Close()
+	go func() {
+		ServeReconcile(y, NewSet(1)) //nolint:errcheck
+		y.Write([]byte("after"))     //nolint:errcheck
+	}()
+	if _, err := Reconcile(x, NewSet(2)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(x, buf); err != nil || string(buf) != "after" {
+		t.Errorf("read after Reconcile() = %q, %v", buf, err)
+	}
+}
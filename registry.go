@@ -0,0 +1,212 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"sort"
+	"sync"
+)
+
+// Registry is a thread safe collection of named sets, all sets in it are
+// thread safe. It is used to share sets with other services, for example
+// by the resp package.
+//
+// The mutations through the Registry are serialized, so Add and Remove
+// report the exact number of changed elements, as long as the sets are
+// not modified elsewhere. Each of the mutations gives the set a new
+// version, which is never reused by the registry even if the set is
+// deleted and created again.
+type Registry struct {
+	mu   sync.RWMutex
+	sets map[string]registryEntry
+	// rev is the last version given to a set
+	rev uint64
+}
+
+type registryEntry struct {
+	set     Set
+	version uint64
+}
+
+func newRegistry() *Registry {
+	return &Registry{sets: make(map[string]registryEntry)}
+}
+
+// put stores s with a new version, or deletes it if s is empty. The write
+// lock must be held.
+func (r *Registry) put(name string, s Set) uint64 {
+	if s.Len() == 0 {
+		delete(r.sets, name)
+		return 0
+	}
+	r.rev++
+	r.sets[name] = registryEntry{set: s, version: r.rev}
+	return r.rev
+}
+
+// Get returns the set of the given name.
+func (r *Registry) Get(name string) (Set, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.sets[name]
+	return e.set, ok
+}
+
+// Version returns the version of the set of the given name, or zero if it
+// does not exist.
+func (r *Registry) Version(name string) uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.sets[name].version
+}
+
+// Snapshot returns a thread unsafe copy of the set of the given name and
+// its version.
+func (r *Registry) Snapshot(name string) (Set, uint64, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.sets[name]
+	if !ok {
+		return nil, 0, false
+	}
+	return e.set.Copy().ToThreadUnsafe(), e.version, true
+}
+
+// Sets returns the sets of the given names in order, a missing set is
+// returned as an empty one.
+func (r *Registry) Sets(names ...string) []Set {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ret := make([]Set, len(names))
+	for i, name := range names {
+		e, ok := r.sets[name]
+		if !ok {
+			e.set = newThreadSafeSet()
+		}
+		ret[i] = e.set
+	}
+	return ret
+}
+
+// Store stores the set with the given name, replacing the existing one.
+// The set is converted by ToThreadSafe. Unlike the other mutations, an
+// empty set is stored too.
+func (r *Registry) Store(name string, s Set) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rev++
+	r.sets[name] = registryEntry{set: s.ToThreadSafe(), version: r.rev}
+}
+
+// Update calls f with the set of the given name and its version, or a new
+// empty set and zero if it does not exist, while holding the write lock
+// of the registry. The set returned by f replaces the existing one with a
+// new version, which is returned by Update. An empty or nil set deletes
+// the existing one, and the returned version is zero.
+//
+// If f returns an error, the registry is left unchanged and the error is
+// returned, so f can check the version to apply optimistic concurrency
+// control. f must not modify the given set in this case.
+func (r *Registry) Update(name string, f func(s Set, version uint64) (Set, error)) (uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.sets[name]
+	if !ok {
+		e.set = newThreadSafeSet()
+	}
+	s, err := f(e.set, e.version)
+	if err != nil {
+		return 0, err
+	}
+	if s == nil {
+		s = newThreadSafeSet()
+	}
+	return r.put(name, s.ToThreadSafe()), nil
+}
+
+// Delete deletes the sets of the given names, and returns the number of
+// deleted sets.
+func (r *Registry) Delete(names ...string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, name := range names {
+		if _, ok := r.sets[name]; ok {
+			delete(r.sets, name)
+			n++
+		}
+	}
+	return n
+}
+
+// Add adds the elements to the set of the given name, which is created if
+// it does not exist. It returns the number of elements not in the set
+// before. Like Set.Add, nothing is added if it returns an error.
+func (r *Registry) Add(name string, elems ...interface{}) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.sets[name]
+	if !ok {
+		e.set = newThreadSafeSet()
+	}
+	before := e.set.Len()
+	if err := e.set.Add(elems...); err != nil {
+		return 0, err
+	}
+	n := e.set.Len() - before
+	if n > 0 {
+		r.put(name, e.set)
+	}
+	return n, nil
+}
+
+// Remove removes the elements from the set of the given name, and returns
+// the number of removed elements. The set is deleted once it is empty.
+func (r *Registry) Remove(name string, elems ...interface{}) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.sets[name]
+	if !ok {
+		return 0
+	}
+	before := e.set.Len()
+	e.set.Remove(elems...)
+	n := before - e.set.Len()
+	if n > 0 {
+		r.put(name, e.set)
+	}
+	return n
+}
+
+// Names returns the sorted names of all sets.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ret := make([]string, 0, len(r.sets))
+	for name := range r.sets {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Len returns the number of sets.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.sets)
+}
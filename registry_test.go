@@ -0,0 +1,147 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func Test_Registry(t *testing.T) {
+	r := NewRegistry()
+	if n, err := r.Add("a", 1, 2, 2, "x"); n != 3 || err != nil {
+		t.Errorf("Add() = %v, %v, want 3, nil", n, err)
+	}
+	if n, _ := r.Add("a", 2, 3); n != 1 {
+		t.Errorf("Add() = %v, want 1", n)
+	}
+	if _, err := r.Add("b", []int{1}); !errors.Is(err, ErrUnhashable) {
+		t.Errorf("Add() error = %v, want ErrUnhashable", err)
+	}
+	if _, ok := r.Get("b"); ok {
+		t.Errorf("Add() with error creates the set")
+	}
+	r.Store("b", NewSet(3, 4))
+	if s, ok := r.Get("b"); !ok || !s.Equal(NewSet(3, 4)) {
+		t.Errorf("Get() = %v, %v", s, ok)
+	} else if _, safe := s.(*threadSafeSet); !safe {
+		t.Errorf("Store() stores %T, want a thread safe set", s)
+	}
+	if got, want := r.Names(), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+
+	sets := r.Sets("a", "missing", "b")
+	if len(sets) != 3 || !sets[0].Equal(NewSet(1, 2, 3, "x")) || sets[1].Len() != 0 {
+		t.Errorf("Sets() = %v", sets)
+	}
+
+	if n := r.Remove("b", 4, 5); n != 1 {
+		t.Errorf("Remove() = %v, want 1", n)
+	}
+	if n := r.Remove("b", 3); n != 1 || r.Len() != 1 {
+		t.Errorf("Remove() = %v and Len() = %v, want the empty set deleted", n, r.Len())
+	}
+	if n := r.Remove("missing", 3); n != 0 {
+		t.Errorf("Remove() = %v, want 0", n)
+	}
+	if n := r.Delete("a", "missing"); n != 1 || r.Len() != 0 {
+		t.Errorf("Delete() = %v, Len() = %v", n, r.Len())
+	}
+}
+
+func Test_Registry_Version(t *testing.T) {
+	r := NewRegistry()
+	errStale := errors.New("stale")
+	r.Add("a", 1) //nolint:errcheck
+	v1 := r.Version("a")
+	if v1 == 0 {
+		t.Fatalf("Version() = 0 after Add()")
+	}
+	if r.Add("a", 1); r.Version("a") != v1 {
+		t.Errorf("Add() of existing elements changes the version")
+	}
+	if r.Remove("a", 2); r.Version("a") != v1 {
+		t.Errorf("Remove() of missing elements changes the version")
+	}
+
+	v2, err := r.Update("a", func(s Set, version uint64) (Set, error) {
+		if version != v1 {
+			return nil, errStale
+		}
+		s.Add(2) //nolint:errcheck
+		return s, nil
+	})
+	if err != nil || v2 <= v1 || r.Version("a") != v2 {
+		t.Errorf("Update() = %v, %v, want a new version", v2, err)
+	}
+	if _, err := r.Update("a", func(s Set, version uint64) (Set, error) {
+		if version != v1 {
+			return nil, errStale
+		}
+		return NewSet(), nil
+	}); err != errStale || r.Version("a") != v2 {
+		t.Errorf("Update() with stale version = %v", err)
+	}
+
+	s, version, ok := r.Snapshot("a")
+	if !ok || version != v2 || !s.Equal(NewSet(1, 2)) {
+		t.Errorf("Snapshot() = %v, %v, %v", s, version, ok)
+	}
+	s.Add(3) //nolint:errcheck
+	if got, _ := r.Get("a"); got.Contains(3) {
+		t.Errorf("Snapshot() returns the set itself")
+	}
+
+	if v, err := r.Update("a", func(Set, uint64) (Set, error) { return nil, nil }); v != 0 || err != nil || r.Len() != 0 {
+		t.Errorf("Update() to nil = %v, %v, Len() = %v", v, err, r.Len())
+	}
+	if _, _, ok := r.Snapshot("a"); ok || r.Version("a") != 0 {
+		t.Errorf("Snapshot() of a deleted set is found")
+	}
+	r.Add("a", 1) //nolint:errcheck
+	if r.Version("a") <= v2 {
+		t.Errorf("Version() = %v of a recreated set, want greater than %v", r.Version("a"), v2)
+	}
+}
+
+func Test_Registry_Concurrent(t *testing.T) {
+	r := NewRegistry()
+	var wg sync.WaitGroup
+	added := make([]int, 8)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				n, _ := r.Add("s", i)
+				added[g] += n
+				IntersectAll(r.Sets("s", "s")...)
+			}
+		}(g)
+	}
+	wg.Wait()
+	total := 0
+	for _, n := range added {
+		total += n
+	}
+	if s, _ := r.Get("s"); total != 100 || s.Len() != 100 {
+		t.Errorf("Add() concurrently reports %v added elements, want 100", total)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zoumo/goset"
+)
+
+// command is a command served by Server.
+type command struct {
+	// arity is the number of arguments including the command name, a
+	// negative arity means at least -arity arguments, like Redis.
+	arity int
+	run   func(s *Server, w *writer, args []string)
+}
+
+var commands = map[string]command{
+	"ping":        {-1, ping},
+	"quit":        {1, quit},
+	"command":     {-1, commandInfo},
+	"del":         {-2, del},
+	"exists":      {-2, exists},
+	"sadd":        {-3, sadd},
+	"srem":        {-3, srem},
+	"sismember":   {3, sismember},
+	"smembers":    {2, smembers},
+	"scard":       {2, scard},
+	"sinter":      {-2, setOperation(goset.IntersectAll, false)},
+	"sunion":      {-2, setOperation(goset.UniteAll, false)},
+	"sdiff":       {-2, setOperation(diffAll, false)},
+	"sinterstore": {-3, setOperation(goset.IntersectAll, true)},
+	"sunionstore": {-3, setOperation(goset.UniteAll, true)},
+	"sdiffstore":  {-3, setOperation(diffAll, true)},
+}
+
+// dispatch runs the command of args and writes its reply.
+func (s *Server) dispatch(w *writer, args []string) {
+	name := strings.ToLower(args[0])
+	cmd, ok := commands[name]
+	if !ok {
+		w.writeError(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+		return
+	}
+	if cmd.arity > 0 && len(args) != cmd.arity || cmd.arity < 0 && len(args) < -cmd.arity {
+		w.writeError(fmt.Sprintf("ERR wrong number of arguments for '%s' command", name))
+		return
+	}
+	cmd.run(s, w, args)
+}
+
+func members(args []string) []interface{} {
+	ret := make([]interface{}, len(args))
+	for i, arg := range args {
+		ret[i] = arg
+	}
+	return ret
+}
+
+func writeSet(w *writer, set goset.Set) {
+	// take a snapshot, so that the length always matches the elements even
+	// if the set is changed concurrently
+	elems := set.Elements()
+	w.writeArrayLen(len(elems))
+	for _, elem := range elems {
+		w.writeBulk(fmt.Sprint(elem))
+	}
+}
+
+func ping(_ *Server, w *writer, args []string) {
+	if len(args) > 1 {
+		w.writeBulk(args[1])
+		return
+	}
+	w.writeSimple("PONG")
+}
+
+// quit replies OK, the connection is closed by the server.
+func quit(_ *Server, w *writer, _ []string) {
+	w.writeSimple("OK")
+}
+
+// commandInfo replies an empty array, clients send it for introspection
+// when they connect.
+func commandInfo(_ *Server, w *writer, _ []string) {
+	w.writeArrayLen(0)
+}
+
+func del(s *Server, w *writer, args []string) {
+	w.writeInt(s.registry.Delete(args[1:]...))
+}
+
+func exists(s *Server, w *writer, args []string) {
+	n := 0
+	for _, name := range args[1:] {
+		if _, ok := s.registry.Get(name); ok {
+			n++
+		}
+	}
+	w.writeInt(n)
+}
+
+func sadd(s *Server, w *writer, args []string) {
+	n, err := s.registry.Add(args[1], members(args[2:])...)
+	if err != nil {
+		w.writeError("ERR " + err.Error())
+		return
+	}
+	w.writeInt(n)
+}
+
+func srem(s *Server, w *writer, args []string) {
+	w.writeInt(s.registry.Remove(args[1], members(args[2:])...))
+}
+
+func sismember(s *Server, w *writer, args []string) {
+	set, ok := s.registry.Get(args[1])
+	if ok && set.Contains(args[2]) {
+		w.writeInt(1)
+		return
+	}
+	w.writeInt(0)
+}
+
+func smembers(s *Server, w *writer, args []string) {
+	writeSet(w, s.registry.Sets(args[1])[0])
+}
+
+func scard(s *Server, w *writer, args []string) {
+	w.writeInt(s.registry.Sets(args[1])[0].Len())
+}
+
+func diffAll(sets ...goset.Set) goset.Set {
+	return goset.DiffAll(sets[0], sets[1:]...)
+}
+
+// setOperation returns the command of op on the sets of the keys, the
+// *STORE variants store the result in the first key and reply its
+// cardinality, an empty result deletes the key like Redis.
+func setOperation(op func(sets ...goset.Set) goset.Set, store bool) func(s *Server, w *writer, args []string) {
+	return func(s *Server, w *writer, args []string) {
+		keys := args[1:]
+		if store {
+			keys = args[2:]
+		}
+		ret := op(s.registry.Sets(keys...)...)
+		if !store {
+			writeSet(w, ret)
+			return
+		}
+		if ret.Len() == 0 {
+			s.registry.Delete(args[1])
+		} else {
+			s.registry.Store(args[1], ret)
+		}
+		w.writeInt(ret.Len())
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	// maxArgs is the maximum number of arguments of a command.
+	maxArgs = 1024 * 1024
+	// maxBulkLen is the maximum length of an argument.
+	maxBulkLen = 64 * 1024 * 1024
+	// maxInlineLen is the maximum length of an inline command.
+	maxInlineLen = 64 * 1024
+)
+
+// protocolError is returned by reader if the client sends malformed
+// requests, the connection is closed after replying it.
+type protocolError string
+
+func (e protocolError) Error() string {
+	return "Protocol error: " + string(e)
+}
+
+// reader reads commands sent by clients, which are either arrays of bulk
+// strings or inline commands separated by spaces, like telnet sends.
+type reader struct {
+	r *bufio.Reader
+}
+
+// readLine reads a line terminated by CRLF, or LF for inline commands.
+func (r *reader) readLine() ([]byte, error) {
+	line, err := r.r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return nil, protocolError("too big request")
+	}
+	if err != nil {
+		return nil, err
+	}
+	line = line[:len(line)-1]
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	return line, nil
+}
+
+// readCommand reads a command and its arguments, it returns no arguments
+// for an empty inline command.
+func (r *reader) readCommand() ([]string, error) {
+	line, err := r.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		if len(line) > maxInlineLen {
+			return nil, protocolError("too big inline request")
+		}
+		fields := bytes.Fields(line)
+		args := make([]string, len(fields))
+		for i, field := range fields {
+			args[i] = string(field)
+		}
+		return args, nil
+	}
+
+	n, err := strconv.Atoi(string(line[1:]))
+	if err != nil || n > maxArgs {
+		return nil, protocolError("invalid multibulk length")
+	}
+	args := make([]string, 0, maxInt(n, 0))
+	for i := 0; i < n; i++ {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, protocolError(fmt.Sprintf("expected '$', got '%s'", line))
+		}
+		size, err := strconv.Atoi(string(line[1:]))
+		if err != nil || size < 0 || size > maxBulkLen {
+			return nil, protocolError("invalid bulk length")
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r.r, buf); err != nil {
+			return nil, err
+		}
+		if buf[size] != '\r' || buf[size+1] != '\n' {
+			return nil, protocolError("bulk string not terminated by CRLF")
+		}
+		args = append(args, string(buf[:size]))
+	}
+	return args, nil
+}
+
+// writer writes replies to clients.
+type writer struct {
+	w *bufio.Writer
+}
+
+func (w *writer) writeSimple(s string) {
+	w.w.WriteByte('+')
+	w.w.WriteString(s)
+	w.w.WriteString("\r\n")
+}
+
+func (w *writer) writeError(msg string) {
+	w.w.WriteByte('-')
+	w.w.WriteString(msg)
+	w.w.WriteString("\r\n")
+}
+
+func (w *writer) writeInt(n int) {
+	w.w.WriteByte(':')
+	w.w.WriteString(strconv.Itoa(n))
+	w.w.WriteString("\r\n")
+}
+
+func (w *writer) writeBulk(s string) {
+	w.w.WriteByte('$')
+	w.w.WriteString(strconv.Itoa(len(s)))
+	w.w.WriteString("\r\n")
+	w.w.WriteString(s)
+	w.w.WriteString("\r\n")
+}
+
+func (w *writer) writeArrayLen(n int) {
+	w.w.WriteByte('*')
+	w.w.WriteString(strconv.Itoa(n))
+	w.w.WriteString("\r\n")
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
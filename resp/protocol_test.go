@@ -0,0 +1,88 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_reader_readCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    [][]string
+		wantErr string
+	}{
+		{"multibulk", "*2\r\n$5\r\nSCARD\r\n$3\r\na b\r\n", [][]string{{"SCARD", "a b"}}, ""},
+		{"binary safe", "*2\r\n$4\r\nECHO\r\n$4\r\na\r\nb\r\n", [][]string{{"ECHO", "a\r\nb"}}, ""},
+		{"empty bulk", "*2\r\n$4\r\nSADD\r\n$0\r\n\r\n", [][]string{{"SADD", ""}}, ""},
+		{"inline", "SADD a  b\r\nPING\n\r\n", [][]string{{"SADD", "a", "b"}, {"PING"}, {}}, ""},
+		{"empty multibulk", "*0\r\n", [][]string{{}}, ""},
+		{"invalid multibulk length", "*x\r\n", nil, "Protocol error: invalid multibulk length"},
+		{"missing bulk", "*1\r\n:1\r\n", nil, "Protocol error: expected '$', got ':1'"},
+		{"invalid bulk length", "*1\r\n$-1\r\n", nil, "Protocol error: invalid bulk length"},
+		{"unterminated bulk", "*1\r\n$1\r\nabc\r\n", nil, "Protocol error: bulk string not terminated by CRLF"},
+		{"truncated", "*2\r\n$4\r\nPING\r\n", [][]string{}, "EOF"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			r := &reader{r: bufio.NewReader(strings.NewReader(tt.input))}
+			got := [][]string{}
+			var err error
+			for {
+				var args []string
+				args, err = r.readCommand()
+				if err != nil {
+					break
+				}
+				got = append(got, args)
+			}
+			if tt.wantErr == "" && err != io.EOF || tt.wantErr != "" && err.Error() != tt.wantErr {
+				t.Errorf("readCommand() error = %v, want %v", err, tt.wantErr)
+			}
+			var perr protocolError
+			if tt.want == nil && !errors.As(err, &perr) {
+				t.Errorf("readCommand() error = %v, want protocolError", err)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writer(t *testing.T) {
+	var b strings.Builder
+	w := &writer{w: bufio.NewWriter(&b)}
+	w.writeSimple("OK")
+	w.writeError("ERR bad")
+	w.writeInt(-3)
+	w.writeArrayLen(2)
+	w.writeBulk("a\r\nb")
+	w.writeBulk("")
+	w.w.Flush()
+	want := "+OK\r\n-ERR bad\r\n:-3\r\n*2\r\n$4\r\na\r\nb\r\n$0\r\n\r\n"
+	if got := b.String(); got != want {
+		t.Errorf("writer wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resp serves the named sets of a goset.Registry over the Redis
+// serialization protocol (RESP), so that services in other languages can
+// share sets with a Go service by any Redis client.
+//
+// The supported commands are PING, QUIT, COMMAND, DEL, EXISTS, SADD, SREM,
+// SISMEMBER, SMEMBERS, SCARD, SINTER, SUNION, SDIFF, SINTERSTORE,
+// SUNIONSTORE and SDIFFSTORE. Members sent by clients are stored as
+// strings, and elements of other types in the registry are replied in
+// their fmt.Sprint forms.
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/zoumo/goset"
+)
+
+// ErrServerClosed is returned by Serve and ListenAndServe after Close.
+var ErrServerClosed = errors.New("resp: server closed")
+
+// Server serves the sets of a Registry over RESP.
+type Server struct {
+	registry *goset.Registry
+
+	mu        sync.Mutex
+	closed    bool
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+}
+
+// NewServer returns a new Server of the given registry.
+func NewServer(registry *goset.Registry) *Server {
+	return &Server{
+		registry:  registry,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+	}
+}
+
+// ListenAndServe listens on the TCP address and serves the connections.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on the listener and serves each of them in a
+// new goroutine. It always returns a non-nil error and closes l, the error
+// is ErrServerClosed after Close.
+func (s *Server) Serve(l net.Listener) error {
+	if !s.track(l, nil) {
+		l.Close()
+		return ErrServerClosed
+	}
+	defer s.untrack(l, nil)
+	defer l.Close()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			return err
+		}
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeConn serves the connection until the client quits or the
+// connection is closed, and then closes it.
+func (s *Server) ServeConn(conn net.Conn) {
+	defer conn.Close()
+	if !s.track(nil, conn) {
+		return
+	}
+	defer s.untrack(nil, conn)
+
+	r := &reader{r: bufio.NewReader(conn)}
+	w := &writer{w: bufio.NewWriter(conn)}
+	for {
+		args, err := r.readCommand()
+		if err != nil {
+			var perr protocolError
+			if errors.As(err, &perr) {
+				w.writeError("ERR " + perr.Error())
+				w.w.Flush()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		s.dispatch(w, args)
+		// flush once the pipelined commands are all served
+		if r.r.Buffered() == 0 || isQuit(args) {
+			if err := w.w.Flush(); err != nil {
+				return
+			}
+		}
+		if isQuit(args) {
+			return
+		}
+	}
+}
+
+func isQuit(args []string) bool {
+	return len(args) == 1 && strings.EqualFold(args[0], "quit")
+}
+
+// Close closes all listeners and connections of the server.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	var err error
+	for l := range s.listeners {
+		if cerr := l.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	return err
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// track tracks the listener or connection, it returns false if the server
+// is closed.
+func (s *Server) track(l net.Listener, conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	if l != nil {
+		s.listeners[l] = struct{}{}
+	}
+	if conn != nil {
+		s.conns[conn] = struct{}{}
+	}
+	return true
+}
+
+func (s *Server) untrack(l net.Listener, conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.listeners, l)
+	delete(s.conns, conn)
+}
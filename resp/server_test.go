@@ -0,0 +1,271 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/zoumo/goset"
+)
+
+// testClient is a minimal RESP client.
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+type replyError string
+
+func startServer(t *testing.T, registry *goset.Registry) (*Server, string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(registry)
+	go s.Serve(l) //nolint:errcheck
+	t.Cleanup(func() { s.Close() })
+	return s, l.Addr().String()
+}
+
+func dial(t *testing.T, addr string) *testClient {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &testClient{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+func (c *testClient) send(args ...string) {
+	buf := fmt.Sprintf("*%d\r\n", len(args))
+	for _, arg := range args {
+		buf += fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, buf); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testClient) do(args ...string) interface{} {
+	c.send(args...)
+	return c.read()
+}
+
+// read reads a reply, arrays of bulk strings are sorted.
+func (c *testClient) read() interface{} {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	line = line[:len(line)-2]
+	switch line[0] {
+	case '+':
+		return line[1:]
+	case '-':
+		return replyError(line[1:])
+	case ':':
+		n, _ := strconv.Atoi(line[1:])
+		return n
+	case '$':
+		n, _ := strconv.Atoi(line[1:])
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			c.t.Fatal(err)
+		}
+		return string(buf[:n])
+	case '*':
+		n, _ := strconv.Atoi(line[1:])
+		ret := make([]string, n)
+		for i := range ret {
+			ret[i] = c.read().(string)
+		}
+		sort.Strings(ret)
+		return ret
+	}
+	c.t.Fatalf("unexpected reply %q", line)
+	return nil
+}
+
+func Test_Server_Commands(t *testing.T) {
+	registry := goset.NewRegistry()
+	registry.Store("go", goset.NewSet(1, "a"))
+	_, addr := startServer(t, registry)
+	c := dial(t, addr)
+
+	tests := []struct {
+		args []string
+		want interface{}
+	}{
+		{[]string{"PING"}, "PONG"},
+		{[]string{"ping", "hi"}, "hi"},
+		{[]string{"COMMAND", "DOCS"}, []string{}},
+		{[]string{"SADD", "a", "x", "y", "x"}, 2},
+		{[]string{"sadd", "a", "y", "z"}, 1},
+		{[]string{"SADD", "b", "y", "z", "w"}, 3},
+		{[]string{"SCARD", "a"}, 3},
+		{[]string{"SCARD", "missing"}, 0},
+		{[]string{"SISMEMBER", "a", "x"}, 1},
+		{[]string{"SISMEMBER", "a", "w"}, 0},
+		{[]string{"SISMEMBER", "missing", "x"}, 0},
+		{[]string{"SMEMBERS", "a"}, []string{"x", "y", "z"}},
+		{[]string{"SMEMBERS", "missing"}, []string{}},
+		{[]string{"SMEMBERS", "go"}, []string{"1", "a"}},
+		{[]string{"SINTER", "a", "b"}, []string{"y", "z"}},
+		{[]string{"SINTER", "a", "missing"}, []string{}},
+		{[]string{"SUNION", "a", "b", "missing"}, []string{"w", "x", "y", "z"}},
+		{[]string{"SDIFF", "a", "b"}, []string{"x"}},
+		{[]string{"SDIFF", "missing", "a"}, []string{}},
+		{[]string{"SINTERSTORE", "c", "a", "b"}, 2},
+		{[]string{"SMEMBERS", "c"}, []string{"y", "z"}},
+		{[]string{"SUNIONSTORE", "c", "a", "go"}, 5},
+		{[]string{"SDIFFSTORE", "a", "a", "b"}, 1},
+		{[]string{"SMEMBERS", "a"}, []string{"x"}},
+		{[]string{"SINTERSTORE", "c", "a", "missing"}, 0},
+		{[]string{"EXISTS", "a", "b", "c", "a"}, 3},
+		{[]string{"SREM", "b", "y", "v"}, 1},
+		{[]string{"SREM", "a", "x"}, 1},
+		{[]string{"EXISTS", "a"}, 0},
+		{[]string{"DEL", "b", "missing"}, 1},
+		{[]string{"SADD", "a"}, replyError("ERR wrong number of arguments for 'sadd' command")},
+		{[]string{"SISMEMBER", "a", "x", "y"}, replyError("ERR wrong number of arguments for 'sismember' command")},
+		{[]string{"GET", "a"}, replyError("ERR unknown command 'GET'")},
+		{[]string{"QUIT"}, "OK"},
+	}
+	for _, tt := range tests {
+		if got := c.do(tt.args...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q = %#v, want %#v", tt.args, got, tt.want)
+		}
+	}
+	if _, err := c.r.ReadByte(); err != io.EOF {
+		t.Errorf("QUIT does not close the connection, got %v", err)
+	}
+
+	if s, _ := registry.Get("c"); s != nil {
+		t.Errorf("empty SINTERSTORE result is stored as %v", s)
+	}
+	if s, _ := registry.Get("go"); !s.Equal(goset.NewSet(1, "a")) {
+		t.Errorf("SUNIONSTORE modifies the source set to %v", s)
+	}
+}
+
+func Test_Server_Pipeline(t *testing.T) {
+	_, addr := startServer(t, goset.NewRegistry())
+	c := dial(t, addr)
+
+	// inline commands and pipelined commands in one write
+	if _, err := io.WriteString(c.conn, "SADD s 1 2 3\r\n\r\nSCARD s\r\n*2\r\n$8\r\nSMEMBERS\r\n$1\r\ns\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []interface{}{3, 3, []string{"1", "2", "3"}} {
+		if got := c.read(); !reflect.DeepEqual(got, want) {
+			t.Errorf("pipelined reply = %#v, want %#v", got, want)
+		}
+	}
+
+	io.WriteString(c.conn, "*1\r\n:1\r\n") //nolint:errcheck
+	if got, want := c.read(), replyError("ERR Protocol error: expected '$', got ':1'"); got != want {
+		t.Errorf("protocol error reply = %#v, want %#v", got, want)
+	}
+	if _, err := c.r.ReadByte(); err != io.EOF {
+		t.Errorf("protocol error does not close the connection, got %v", err)
+	}
+}
+
+func Test_Server_Concurrent(t *testing.T) {
+	registry := goset.NewRegistry()
+	_, addr := startServer(t, registry)
+
+	var wg sync.WaitGroup
+	added := make([]int, 4)
+	for g := 0; g < 4; g++ {
+		c := dial(t, addr)
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				added[g] += c.do("SADD", "s", strconv.Itoa(i)).(int)
+				c.do("SUNIONSTORE", "u", "s", "u")
+			}
+		}(g)
+	}
+	wg.Wait()
+	sum := 0
+	for _, n := range added {
+		sum += n
+	}
+	if s, _ := registry.Get("s"); sum != 50 || s.Len() != 50 {
+		t.Errorf("SADD concurrently reports %d added members, want 50", sum)
+	}
+}
+
+// changingSet adds an element whenever Len is called, like a concurrent
+// SADD between reading the length and the elements.
+type changingSet struct {
+	goset.Set
+}
+
+func (s changingSet) Len() int {
+	n := s.Set.Len()
+	s.Set.Add(fmt.Sprint("new-", n)) //nolint:errcheck
+	return n
+}
+
+func Test_writeSet(t *testing.T) {
+	var b bytes.Buffer
+	w := &writer{w: bufio.NewWriter(&b)}
+	writeSet(w, changingSet{goset.NewSet("a")})
+	w.w.Flush()
+	if got, want := b.String(), "*1\r\n$1\r\na\r\n"; got != want {
+		t.Errorf("writeSet() = %q, want %q", got, want)
+	}
+}
+
+func Test_Server_Close(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(goset.NewRegistry())
+	done := make(chan error)
+	go func() { done <- s.Serve(l) }()
+
+	c := dial(t, l.Addr().String())
+	if got := c.do("PING"); got != "PONG" {
+		t.Fatalf("PING = %v", got)
+	}
+	s.Close()
+	if err := <-done; !errors.Is(err, ErrServerClosed) {
+		t.Errorf("Serve() error = %v, want ErrServerClosed", err)
+	}
+	if _, err := c.r.ReadByte(); err == nil {
+		t.Errorf("Close() does not close the connections")
+	}
+	if err := s.Serve(l); !errors.Is(err, ErrServerClosed) {
+		t.Errorf("Serve() after Close() error = %v, want ErrServerClosed", err)
+	}
+}
@@ -17,14 +17,110 @@ limitations under the License.
 package goset
 
 import (
+	"reflect"
+	"sort"
 	"sync"
 )
 
 type threadSafeSet struct {
-	unsafe *set
+	unsafe Set
 	mu     sync.RWMutex
 }
 
+// rlocker is implemented by thread safe sets. It is used to hold the read
+// lock of the other set in operations between two sets.
+type rlocker interface {
+	rlock()
+	runlock()
+	// lockID identifies the lock, sets sharing the same lock have the same
+	// lockID.
+	lockID() uintptr
+}
+
+// locker is implemented by thread safe sets to hold the write lock of the
+// receiver in in-place operations.
+type locker interface {
+	rlocker
+	lock()
+	unlock()
+}
+
+// rlockSets holds the read locks of all thread safe sets in the given ones,
+// and returns the function to release them.
+func rlockSets(sets ...Set) func() {
+	return lockSets(nil, sets...)
+}
+
+// lockSets holds the write lock of w and the read locks of the others if
+// they are thread safe sets, and returns the function to release them.
+// Locks are acquired in the order of their lockIDs and each lock is
+// acquired once, so that concurrent operations on the same sets in
+// different orders never deadlock with pending writers.
+func lockSets(w Set, sets ...Set) func() {
+	type held struct {
+		l     rlocker
+		write bool
+	}
+	locks := make([]held, 0, len(sets)+1)
+	add := func(s Set, write bool) {
+		l, ok := s.(rlocker)
+		if !ok {
+			return
+		}
+		for _, h := range locks {
+			if h.l.lockID() == l.lockID() {
+				return
+			}
+		}
+		locks = append(locks, held{l, write})
+	}
+	if w != nil {
+		add(w, true)
+	}
+	for _, s := range sets {
+		add(s, false)
+	}
+	sort.Slice(locks, func(i, j int) bool {
+		return locks[i].l.lockID() < locks[j].l.lockID()
+	})
+	for _, h := range locks {
+		if h.write {
+			h.l.(locker).lock()
+		} else {
+			h.l.rlock()
+		}
+	}
+	return func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			if locks[i].write {
+				locks[i].l.(locker).unlock()
+			} else {
+				locks[i].l.runlock()
+			}
+		}
+	}
+}
+
+func (s *threadSafeSet) rlock() {
+	s.mu.RLock()
+}
+
+func (s *threadSafeSet) runlock() {
+	s.mu.RUnlock()
+}
+
+func (s *threadSafeSet) lock() {
+	s.mu.Lock()
+}
+
+func (s *threadSafeSet) unlock() {
+	s.mu.Unlock()
+}
+
+func (s *threadSafeSet) lockID() uintptr {
+	return reflect.ValueOf(&s.mu).Pointer()
+}
+
 func newThreadSafeSet(elems ...interface{}) *threadSafeSet {
 	s := &threadSafeSet{
 		unsafe: newSet(),
@@ -44,6 +140,11 @@ func (s *threadSafeSet) Add(elems ...interface{}) error {
 }
 
 func (s *threadSafeSet) Extend(b interface{}) error {
+	if setb, ok := b.(Set); ok {
+		// copy elements out of b first, so that b's lock is never held
+		// together with the write lock of s
+		b = setb.Elements()
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -60,7 +161,7 @@ func (s *threadSafeSet) Copy() Set {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return &threadSafeSet{
-		unsafe: (s.unsafe.Copy()).(*set),
+		unsafe: s.unsafe.Copy(),
 	}
 }
 
@@ -83,6 +184,12 @@ func (s *threadSafeSet) Contains(elem interface{}) bool {
 	return s.unsafe.Contains(elem)
 }
 
+func (s *threadSafeSet) ContainsErr(elem interface{}) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.unsafe.ContainsErr(elem)
+}
+
 func (s *threadSafeSet) ContainsAll(elems ...interface{}) bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -96,38 +203,19 @@ func (s *threadSafeSet) ContainsAny(elems ...interface{}) bool {
 }
 
 func (s *threadSafeSet) Equal(b Set) bool {
-	safeb, ok := b.(*threadSafeSet)
-	if ok {
-		safeb.mu.RLock()
-		defer safeb.mu.RUnlock()
-	}
-
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	defer rlockSets(s, b)()
 
 	return s.unsafe.Equal(b)
 }
 
 func (s *threadSafeSet) IsSubsetOf(b Set) bool {
-	safeb, ok := b.(*threadSafeSet)
-	if ok {
-		safeb.mu.RLock()
-		defer safeb.mu.RUnlock()
-	}
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	defer rlockSets(s, b)()
 
 	return s.unsafe.IsSubsetOf(b)
 }
 
 func (s *threadSafeSet) IsSupersetOf(b Set) bool {
-	safeb, ok := b.(*threadSafeSet)
-	if ok {
-		safeb.mu.RLock()
-		defer safeb.mu.RUnlock()
-	}
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	defer rlockSets(s, b)()
 
 	return s.unsafe.IsSupersetOf(b)
 }
@@ -148,49 +236,25 @@ func (s *threadSafeSet) ToThreadSafe() Set {
 }
 
 func (s *threadSafeSet) Diff(b Set) Set {
-	safeb, ok := b.(*threadSafeSet)
-	if ok {
-		safeb.mu.RLock()
-		defer safeb.mu.RUnlock()
-	}
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	defer rlockSets(s, b)()
 
 	return s.unsafe.Diff(b)
 }
 
 func (s *threadSafeSet) SymmetricDiff(b Set) Set {
-	safeb, ok := b.(*threadSafeSet)
-	if ok {
-		safeb.mu.RLock()
-		defer safeb.mu.RUnlock()
-	}
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	defer rlockSets(s, b)()
 
 	return s.unsafe.SymmetricDiff(b)
 }
 
 func (s *threadSafeSet) Unite(b Set) Set {
-	safeb, ok := b.(*threadSafeSet)
-	if ok {
-		safeb.mu.RLock()
-		defer safeb.mu.RUnlock()
-	}
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	defer rlockSets(s, b)()
 
 	return s.unsafe.Unite(b)
 }
 
 func (s *threadSafeSet) Intersect(b Set) Set {
-	safeb, ok := b.(*threadSafeSet)
-	if ok {
-		safeb.mu.RLock()
-		defer safeb.mu.RUnlock()
-	}
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	defer rlockSets(s, b)()
 
 	return s.unsafe.Intersect(b)
 }
@@ -200,3 +264,23 @@ func (s *threadSafeSet) Range(foreach func(int, interface{}) bool) {
 	defer s.mu.RUnlock()
 	s.unsafe.Range(foreach)
 }
+
+func (s *threadSafeSet) UniteWith(b Set) error {
+	defer lockSets(s, b)()
+	return s.unsafe.UniteWith(b)
+}
+
+func (s *threadSafeSet) IntersectWith(b Set) {
+	defer lockSets(s, b)()
+	s.unsafe.IntersectWith(b)
+}
+
+func (s *threadSafeSet) DiffWith(b Set) {
+	defer lockSets(s, b)()
+	s.unsafe.DiffWith(b)
+}
+
+func (s *threadSafeSet) SymmetricDiffWith(b Set) error {
+	defer lockSets(s, b)()
+	return s.unsafe.SymmetricDiffWith(b)
+}
@@ -36,6 +36,11 @@ func newSet(elems ...interface{}) *set {
 }
 
 func (s *set) Extend(b interface{}) error {
+	return extend(s, b)
+}
+
+// extend adds all elements in b to the set s, b must be array, slice or Set.
+func extend(s Set, b interface{}) error {
 	if b == nil {
 		return nil
 	}
@@ -49,23 +54,62 @@ func (s *set) Extend(b interface{}) error {
 		if v.Kind() != reflect.Array && v.Kind() != reflect.Slice {
 			return fmt.Errorf("error extend set with kind: %v, only support array and slice and Set", v.Kind())
 		}
-		for i := 0; i < v.Len(); i++ {
-			vv := v.Index(i)
-			err := s.Add(vv.Interface())
-			if err != nil {
-				return err
-			}
+		elems := make([]interface{}, v.Len())
+		for i := range elems {
+			elems[i] = v.Index(i).Interface()
 		}
-		return nil
+		// add all elements in one call, so that nothing is added if any of
+		// them is invalid
+		return s.Add(elems...)
 	}
 
-	s2 := setb.ToThreadUnsafe().(*set)
-	s2.Range(func(_ int, elem interface{}) bool {
-		s.Add(elem) //nolint:errcheck
+	return s.Add(setb.Elements()...)
+}
+
+// setOf returns the underlying *set of b. It returns false if b is another
+// implementation of Set, which may hold elements a *set can not hold, so
+// the elements of b must be read by its own Contains and Range instead of
+// being copied to a *set.
+func setOf(b Set) (*set, bool) {
+	unsafe := b.ToThreadUnsafe()
+	s, ok := unsafe.(*set)
+	return s, ok
+}
+
+// isSubsetOf checks whether all elements in s are in the foreign set b.
+func (s *set) isSubsetOf(b Set) bool {
+	ret := true
+	s.Range(func(_ int, elem interface{}) bool {
+		ret = b.Contains(elem)
+		return ret
+	})
+	return ret
+}
+
+// filter returns a new set containing the elements in s for which keep
+// returns true.
+func (s *set) filter(keep func(elem interface{}) bool) *set {
+	ret := newSet()
+	s.Range(func(_ int, elem interface{}) bool {
+		if keep(elem) {
+			// elements of s are always hashable
+			ret.typedSetFor(elem).Add(elem)
+		}
 		return true
 	})
+	return ret
+}
 
-	return nil
+// missingFrom returns the elements in the foreign set b which are not in s.
+func (s *set) missingFrom(b Set) []interface{} {
+	var ret []interface{}
+	b.Range(func(_ int, elem interface{}) bool {
+		if !s.Contains(elem) {
+			ret = append(ret, elem)
+		}
+		return true
+	})
+	return ret
 }
 
 func (s *set) Copy() Set {
@@ -76,21 +120,38 @@ func (s *set) Copy() Set {
 }
 
 func (s *set) Equal(b Set) bool {
-	s2 := b.ToThreadUnsafe().(*set)
+	s2, ok := setOf(b)
+	if !ok {
+		b = b.ToThreadUnsafe()
+		return s.Len() == b.Len() && s.isSubsetOf(b)
+	}
 	return s.typedSetGroup.Equal(s2.typedSetGroup)
 }
 
 func (s *set) IsSubsetOf(b Set) bool {
-	s2 := b.ToThreadUnsafe().(*set)
+	s2, ok := setOf(b)
+	if !ok {
+		b = b.ToThreadUnsafe()
+		return s.Len() <= b.Len() && s.isSubsetOf(b)
+	}
 	return s.typedSetGroup.IsSubsetOf(s2.typedSetGroup)
 }
 
 func (s *set) IsSupersetOf(b Set) bool {
-	s2 := b.ToThreadUnsafe().(*set)
+	s2, ok := setOf(b)
+	if !ok {
+		b = b.ToThreadUnsafe()
+		return b.Len() <= s.Len() && len(s.missingFrom(b)) == 0
+	}
 	return s2.typedSetGroup.IsSubsetOf(s.typedSetGroup)
 }
 
 func (s *set) String() string {
+	return formatSet(s)
+}
+
+// formatSet returns the string representation of the set.
+func formatSet(s Set) string {
 	buf := bytes.Buffer{}
 	buf.WriteString("Set[")
 	s.Range(func(i int, elem interface{}) bool {
@@ -114,23 +175,49 @@ func (s *set) ToThreadSafe() Set {
 }
 
 func (s *set) Diff(b Set) Set {
-	s2 := b.ToThreadUnsafe().(*set)
+	s2, ok := setOf(b)
+	if !ok {
+		b = b.ToThreadUnsafe()
+		return s.filter(func(elem interface{}) bool {
+			return !b.Contains(elem)
+		})
+	}
 	diff := &set{
 		typedSetGroup: s.typedSetGroup.Diff(s2.typedSetGroup),
 	}
 	return diff
 }
 
+// SymmetricDiff returns a new *set, unless the foreign set b holds elements
+// a *set can not hold, then the result is computed by b.
 func (s *set) SymmetricDiff(b Set) Set {
-	s2 := b.ToThreadUnsafe().(*set)
+	s2, ok := setOf(b)
+	if !ok {
+		b = b.ToThreadUnsafe()
+		diff := s.Diff(b).(*set)
+		if err := diff.Add(s.missingFrom(b)...); err != nil {
+			return b.SymmetricDiff(s)
+		}
+		return diff
+	}
 	diff := &set{
 		typedSetGroup: s.typedSetGroup.SymmetricDiff(s2.typedSetGroup),
 	}
 	return diff
 }
 
+// Unite returns a new *set, unless the foreign set b holds elements a *set
+// can not hold, then the result is computed by b.
 func (s *set) Unite(b Set) Set {
-	s2 := b.ToThreadUnsafe().(*set)
+	s2, ok := setOf(b)
+	if !ok {
+		b = b.ToThreadUnsafe()
+		union := s.Copy().(*set)
+		if err := union.Add(s.missingFrom(b)...); err != nil {
+			return b.Unite(s)
+		}
+		return union
+	}
 	union := &set{
 		typedSetGroup: s.typedSetGroup.Unite(s2.typedSetGroup),
 	}
@@ -138,9 +225,75 @@ func (s *set) Unite(b Set) Set {
 }
 
 func (s *set) Intersect(b Set) Set {
-	s2 := b.ToThreadUnsafe().(*set)
+	s2, ok := setOf(b)
+	if !ok {
+		b = b.ToThreadUnsafe()
+		return s.filter(b.Contains)
+	}
 	intersection := &set{
 		typedSetGroup: s.typedSetGroup.Intersect(s2.typedSetGroup),
 	}
 	return intersection
 }
+
+func (s *set) UniteWith(b Set) error {
+	s2, ok := setOf(b)
+	if !ok {
+		// Add is all-or-nothing
+		return s.Add(s.missingFrom(b.ToThreadUnsafe())...)
+	}
+	s.typedSetGroup.UniteWith(s2.typedSetGroup)
+	return nil
+}
+
+func (s *set) IntersectWith(b Set) {
+	s2, ok := setOf(b)
+	if !ok {
+		b = b.ToThreadUnsafe()
+		var removed []interface{}
+		s.Range(func(_ int, elem interface{}) bool {
+			if !b.Contains(elem) {
+				removed = append(removed, elem)
+			}
+			return true
+		})
+		s.Remove(removed...)
+		return
+	}
+	s.typedSetGroup.IntersectWith(s2.typedSetGroup)
+}
+
+func (s *set) DiffWith(b Set) {
+	s2, ok := setOf(b)
+	if !ok {
+		b.ToThreadUnsafe().Range(func(_ int, elem interface{}) bool {
+			s.Remove(elem)
+			return true
+		})
+		return
+	}
+	s.typedSetGroup.DiffWith(s2.typedSetGroup)
+}
+
+func (s *set) SymmetricDiffWith(b Set) error {
+	s2, ok := setOf(b)
+	if !ok {
+		var added, removed []interface{}
+		b.ToThreadUnsafe().Range(func(_ int, elem interface{}) bool {
+			if s.Contains(elem) {
+				removed = append(removed, elem)
+			} else {
+				added = append(added, elem)
+			}
+			return true
+		})
+		// add first, so that the set is left unchanged if it fails
+		if err := s.Add(added...); err != nil {
+			return err
+		}
+		s.Remove(removed...)
+		return nil
+	}
+	s.typedSetGroup.SymmetricDiffWith(s2.typedSetGroup)
+	return nil
+}
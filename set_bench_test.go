@@ -178,3 +178,75 @@ func BenchmarkUnsafeRange10(b *testing.B) {
 func BenchmarkSafeRange10(b *testing.B) {
 	benchmarkRange(b, newThreadSafeSet(), 10)
 }
+
+func benchmarkUnite(b *testing.B, x Set, y Set, scale int) {
+	fill(x, scale)
+	fill(y, 2*scale)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Unite(y)
+	}
+}
+
+func benchmarkUniteWith(b *testing.B, x Set, y Set, scale int) {
+	fill(x, scale)
+	fill(y, 2*scale)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.UniteWith(y)
+	}
+}
+
+func BenchmarkUnsafeUnite100(b *testing.B) {
+	benchmarkUnite(b, newSet(), newSet(), 100)
+}
+
+func BenchmarkUnsafeUniteWith100(b *testing.B) {
+	benchmarkUniteWith(b, newSet(), newSet(), 100)
+}
+
+func BenchmarkSafeUnite100(b *testing.B) {
+	benchmarkUnite(b, newThreadSafeSet(), newThreadSafeSet(), 100)
+}
+
+func BenchmarkSafeUniteWith100(b *testing.B) {
+	benchmarkUniteWith(b, newThreadSafeSet(), newThreadSafeSet(), 100)
+}
+
+func benchmarkIntersect(b *testing.B, x Set, y Set, scale int) {
+	fill(x, scale)
+	fill(y, 2*scale)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Intersect(y)
+	}
+}
+
+func benchmarkIntersectWith(b *testing.B, x Set, y Set, scale int) {
+	fill(x, scale)
+	fill(y, 2*scale)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.IntersectWith(y)
+	}
+}
+
+func BenchmarkUnsafeIntersect100(b *testing.B) {
+	benchmarkIntersect(b, newSet(), newSet(), 100)
+}
+
+func BenchmarkUnsafeIntersectWith100(b *testing.B) {
+	benchmarkIntersectWith(b, newSet(), newSet(), 100)
+}
+
+func BenchmarkSafeIntersect100(b *testing.B) {
+	benchmarkIntersect(b, newThreadSafeSet(), newThreadSafeSet(), 100)
+}
+
+func BenchmarkSafeIntersectWith100(b *testing.B) {
+	benchmarkIntersectWith(b, newThreadSafeSet(), newThreadSafeSet(), 100)
+}
@@ -0,0 +1,510 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// defaultShards is the number of shards used by NewShardedSet if the given
+// number is not positive.
+const defaultShards = 32
+
+// shardedSet partitions elements into shards by their hashes, each shard is
+// a thread safe set with its own lock.
+//
+// Operations on single elements only lock the shard of the element.
+// Operations on the whole set hold the read locks of all shards in order,
+// so that they see a consistent snapshot of the set.
+type shardedSet struct {
+	shards []*threadSafeSet
+}
+
+func newShardedSet(n int, elems ...interface{}) *shardedSet {
+	if n <= 0 {
+		n = defaultShards
+	}
+	s := &shardedSet{
+		shards: make([]*threadSafeSet, n),
+	}
+	for i := range s.shards {
+		s.shards[i] = newThreadSafeSet()
+	}
+	err := s.Add(elems...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// empty returns a new empty sharded set with the same number of shards.
+func (s *shardedSet) empty() *shardedSet {
+	return newShardedSet(len(s.shards))
+}
+
+func (s *shardedSet) shardFor(elem interface{}) *threadSafeSet {
+	return s.shards[hashElem(elem)%uint64(len(s.shards))]
+}
+
+func (s *shardedSet) rlock() {
+	for _, shard := range s.shards {
+		shard.mu.RLock()
+	}
+}
+
+func (s *shardedSet) runlock() {
+	for i := len(s.shards) - 1; i >= 0; i-- {
+		s.shards[i].mu.RUnlock()
+	}
+}
+
+func (s *shardedSet) lock() {
+	for _, shard := range s.shards {
+		shard.mu.Lock()
+	}
+}
+
+func (s *shardedSet) unlock() {
+	for i := len(s.shards) - 1; i >= 0; i-- {
+		s.shards[i].mu.Unlock()
+	}
+}
+
+func (s *shardedSet) lockID() uintptr {
+	return reflect.ValueOf(s).Pointer()
+}
+
+// unsafeLen returns the size of set without locking.
+func (s *shardedSet) unsafeLen() int {
+	l := 0
+	for _, shard := range s.shards {
+		l += shard.unsafe.Len()
+	}
+	return l
+}
+
+// unsafeRange is Range without locking.
+func (s *shardedSet) unsafeRange(foreach func(index int, elem interface{}) bool) {
+	i := 0
+	for _, shard := range s.shards {
+		completed := true
+		shard.unsafe.Range(func(_ int, elem interface{}) bool {
+			if !foreach(i, elem) {
+				completed = false
+				return false
+			}
+			i++
+			return true
+		})
+		if !completed {
+			return
+		}
+	}
+}
+
+// unsafeContains is Contains without locking.
+func (s *shardedSet) unsafeContains(elem interface{}) bool {
+	return s.shardFor(elem).unsafe.Contains(elem)
+}
+
+// unsafeView returns the functions to read the locked set b without
+// locking it again.
+func unsafeView(b Set) (length func() int, contains func(interface{}) bool, foreach func(func(int, interface{}) bool)) {
+	if sharded, ok := b.(*shardedSet); ok {
+		return sharded.unsafeLen, sharded.unsafeContains, sharded.unsafeRange
+	}
+	unsafe := b.ToThreadUnsafe()
+	return unsafe.Len, unsafe.Contains, unsafe.Range
+}
+
+func (s *shardedSet) Add(elems ...interface{}) error {
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		s.shardFor(elem).Add(elem) //nolint:errcheck
+	}
+	return nil
+}
+
+func (s *shardedSet) Extend(b interface{}) error {
+	if setb, ok := b.(Set); ok {
+		b = setb.Elements()
+	}
+	return extend(s, b)
+}
+
+func (s *shardedSet) Remove(elems ...interface{}) {
+	for _, elem := range elems {
+		s.shardFor(elem).Remove(elem)
+	}
+}
+
+func (s *shardedSet) Contains(elem interface{}) bool {
+	return s.shardFor(elem).Contains(elem)
+}
+
+func (s *shardedSet) ContainsErr(elem interface{}) (bool, error) {
+	if !hashable(elem) {
+		return false, unhashableError(elem, 0)
+	}
+	return s.shardFor(elem).Contains(elem), nil
+}
+
+func (s *shardedSet) ContainsAll(elems ...interface{}) bool {
+	for _, elem := range elems {
+		if !s.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *shardedSet) ContainsAny(elems ...interface{}) bool {
+	for _, elem := range elems {
+		if s.Contains(elem) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *shardedSet) Copy() Set {
+	s.rlock()
+	defer s.runlock()
+	c := &shardedSet{
+		shards: make([]*threadSafeSet, len(s.shards)),
+	}
+	for i, shard := range s.shards {
+		c.shards[i] = &threadSafeSet{unsafe: shard.unsafe.Copy()}
+	}
+	return c
+}
+
+func (s *shardedSet) Len() int {
+	s.rlock()
+	defer s.runlock()
+	return s.unsafeLen()
+}
+
+func (s *shardedSet) String() string {
+	return formatSet(s)
+}
+
+func (s *shardedSet) Range(foreach func(index int, elem interface{}) bool) {
+	s.rlock()
+	defer s.runlock()
+	s.unsafeRange(foreach)
+}
+
+func (s *shardedSet) Elements() []interface{} {
+	s.rlock()
+	defer s.runlock()
+	ret := make([]interface{}, 0, s.unsafeLen())
+	s.unsafeRange(func(_ int, elem interface{}) bool {
+		ret = append(ret, elem)
+		return true
+	})
+	return ret
+}
+
+func (s *shardedSet) ToStrings() []string {
+	s.rlock()
+	defer s.runlock()
+	ret := []string{}
+	for _, shard := range s.shards {
+		ret = append(ret, shard.unsafe.ToStrings()...)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func (s *shardedSet) ToInts() []int {
+	s.rlock()
+	defer s.runlock()
+	ret := []int{}
+	for _, shard := range s.shards {
+		ret = append(ret, shard.unsafe.ToInts()...)
+	}
+	sort.Ints(ret)
+	return ret
+}
+
+// ToThreadUnsafe returns a thread unsafe copy of the set, since there is no
+// single underlying set of a sharded set.
+func (s *shardedSet) ToThreadUnsafe() Set {
+	ret := newSet()
+	s.unsafeRange(func(_ int, elem interface{}) bool {
+		ret.Add(elem) //nolint:errcheck
+		return true
+	})
+	return ret
+}
+
+func (s *shardedSet) ToThreadSafe() Set {
+	return s
+}
+
+func (s *shardedSet) Equal(b Set) bool {
+	defer rlockSets(s, b)()
+
+	length, contains, _ := unsafeView(b)
+	return s.unsafeLen() == length() && s.isSubsetOf(contains)
+}
+
+func (s *shardedSet) IsSubsetOf(b Set) bool {
+	defer rlockSets(s, b)()
+
+	length, contains, _ := unsafeView(b)
+	return s.unsafeLen() <= length() && s.isSubsetOf(contains)
+}
+
+func (s *shardedSet) IsSupersetOf(b Set) bool {
+	defer rlockSets(s, b)()
+
+	length, _, foreach := unsafeView(b)
+	if length() > s.unsafeLen() {
+		return false
+	}
+	ret := true
+	foreach(func(_ int, elem interface{}) bool {
+		ret = s.unsafeContains(elem)
+		return ret
+	})
+	return ret
+}
+
+func (s *shardedSet) isSubsetOf(contains func(interface{}) bool) bool {
+	ret := true
+	s.unsafeRange(func(_ int, elem interface{}) bool {
+		ret = contains(elem)
+		return ret
+	})
+	return ret
+}
+
+// filter returns a new sharded set containing the elements in s for which
+// keep returns true.
+func (s *shardedSet) filter(keep func(elem interface{}) bool) *shardedSet {
+	ret := s.empty()
+	for i, shard := range s.shards {
+		// the element stays in the same shard since ret has the same
+		// number of shards
+		unsafe := ret.shards[i].unsafe
+		shard.unsafe.Range(func(_ int, elem interface{}) bool {
+			if keep(elem) {
+				unsafe.Add(elem) //nolint:errcheck
+			}
+			return true
+		})
+	}
+	return ret
+}
+
+func (s *shardedSet) Diff(b Set) Set {
+	defer rlockSets(s, b)()
+
+	_, contains, _ := unsafeView(b)
+	return s.filter(func(elem interface{}) bool {
+		return !contains(elem)
+	})
+}
+
+// checkViewHashable is checkSetHashable for the locked set b.
+func checkViewHashable(b Set) error {
+	if _, ok := b.(*shardedSet); ok {
+		// shards hold hashable elements only
+		return nil
+	}
+	return checkSetHashable(b.ToThreadUnsafe())
+}
+
+// SymmetricDiff returns a new sharded set, unless b holds elements which
+// are not hashable, then the result is computed like set.SymmetricDiff.
+func (s *shardedSet) SymmetricDiff(b Set) Set {
+	defer rlockSets(s, b)()
+
+	if checkViewHashable(b) != nil {
+		return s.ToThreadUnsafe().SymmetricDiff(b.ToThreadUnsafe())
+	}
+	_, contains, foreach := unsafeView(b)
+	diff := s.filter(func(elem interface{}) bool {
+		return !contains(elem)
+	})
+	foreach(func(_ int, elem interface{}) bool {
+		if !s.unsafeContains(elem) {
+			diff.shardFor(elem).unsafe.Add(elem) //nolint:errcheck // checked above
+		}
+		return true
+	})
+	return diff
+}
+
+// Unite returns a new sharded set, unless b holds elements which are not
+// hashable, then the result is computed like set.Unite.
+func (s *shardedSet) Unite(b Set) Set {
+	defer rlockSets(s, b)()
+
+	if checkViewHashable(b) != nil {
+		return s.ToThreadUnsafe().Unite(b.ToThreadUnsafe())
+	}
+	_, _, foreach := unsafeView(b)
+	union := s.filter(func(interface{}) bool {
+		return true
+	})
+	foreach(func(_ int, elem interface{}) bool {
+		union.shardFor(elem).unsafe.Add(elem) //nolint:errcheck // checked above
+		return true
+	})
+	return union
+}
+
+func (s *shardedSet) Intersect(b Set) Set {
+	defer rlockSets(s, b)()
+
+	_, contains, _ := unsafeView(b)
+	return s.filter(contains)
+}
+
+// sameShards returns b as a sharded set if it has the same number of shards
+// as s, so that operations can be done shard by shard.
+func (s *shardedSet) sameShards(b Set) (*shardedSet, bool) {
+	sb, ok := b.(*shardedSet)
+	return sb, ok && len(sb.shards) == len(s.shards)
+}
+
+func (s *shardedSet) UniteWith(b Set) error {
+	defer lockSets(s, b)()
+
+	if sb, ok := s.sameShards(b); ok {
+		for i, shard := range s.shards {
+			// shards hold hashable elements only, it never fails
+			shard.unsafe.UniteWith(sb.shards[i].unsafe) //nolint:errcheck
+		}
+		return nil
+	}
+	// check all elements first, so that the set is left unchanged if it
+	// fails
+	if err := checkViewHashable(b); err != nil {
+		return err
+	}
+	_, _, foreach := unsafeView(b)
+	foreach(func(_ int, elem interface{}) bool {
+		s.shardFor(elem).unsafe.Add(elem) //nolint:errcheck // checked above
+		return true
+	})
+	return nil
+}
+
+func (s *shardedSet) IntersectWith(b Set) {
+	defer lockSets(s, b)()
+
+	if sb, ok := s.sameShards(b); ok {
+		for i, shard := range s.shards {
+			shard.unsafe.IntersectWith(sb.shards[i].unsafe)
+		}
+		return
+	}
+	_, contains, _ := unsafeView(b)
+	for _, shard := range s.shards {
+		shard.unsafe.Range(func(_ int, elem interface{}) bool {
+			if !contains(elem) {
+				shard.unsafe.Remove(elem)
+			}
+			return true
+		})
+	}
+}
+
+func (s *shardedSet) DiffWith(b Set) {
+	defer lockSets(s, b)()
+
+	if sb, ok := s.sameShards(b); ok {
+		for i, shard := range s.shards {
+			shard.unsafe.DiffWith(sb.shards[i].unsafe)
+		}
+		return
+	}
+	_, _, foreach := unsafeView(b)
+	foreach(func(_ int, elem interface{}) bool {
+		s.shardFor(elem).unsafe.Remove(elem)
+		return true
+	})
+}
+
+func (s *shardedSet) SymmetricDiffWith(b Set) error {
+	defer lockSets(s, b)()
+
+	if sb, ok := s.sameShards(b); ok {
+		for i, shard := range s.shards {
+			// shards hold hashable elements only, it never fails
+			shard.unsafe.SymmetricDiffWith(sb.shards[i].unsafe) //nolint:errcheck
+		}
+		return nil
+	}
+	if err := checkViewHashable(b); err != nil {
+		return err
+	}
+	_, _, foreach := unsafeView(b)
+	foreach(func(_ int, elem interface{}) bool {
+		shard := s.shardFor(elem).unsafe
+		if shard.Contains(elem) {
+			shard.Remove(elem)
+		} else {
+			shard.Add(elem) //nolint:errcheck // checked above
+		}
+		return true
+	})
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *shardedSet) MarshalJSON() ([]byte, error) {
+	s.rlock()
+	defer s.runlock()
+	return json.Marshal(s.ToThreadUnsafe())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces all elements in
+// the set with the decoded ones.
+func (s *shardedSet) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalElements(data)
+	if err != nil {
+		return err
+	}
+	if elems == nil {
+		return nil
+	}
+	if len(s.shards) == 0 {
+		*s = *newShardedSet(defaultShards)
+	}
+	decoded := s.empty()
+	if err := decoded.Add(elems...); err != nil {
+		return err
+	}
+
+	for _, shard := range s.shards {
+		shard.mu.Lock()
+	}
+	for i, shard := range s.shards {
+		shard.unsafe = decoded.shards[i].unsafe
+		shard.mu.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"sync"
+	"testing"
+)
+
+// the following benchmarks compare the sharded set with the thread safe
+// set under concurrent workloads, b.N operations are split among the
+// given number of goroutines.
+
+func benchmarkConcurrentAdd(b *testing.B, s Set, goroutines int) {
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < b.N; i += goroutines {
+				s.Add(i)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func BenchmarkSafeConcurrentAdd1(b *testing.B) {
+	benchmarkConcurrentAdd(b, NewSafeSet(), 1)
+}
+
+func BenchmarkShardedConcurrentAdd1(b *testing.B) {
+	benchmarkConcurrentAdd(b, NewShardedSet(0), 1)
+}
+
+func BenchmarkSafeConcurrentAdd8(b *testing.B) {
+	benchmarkConcurrentAdd(b, NewSafeSet(), 8)
+}
+
+func BenchmarkShardedConcurrentAdd8(b *testing.B) {
+	benchmarkConcurrentAdd(b, NewShardedSet(0), 8)
+}
+
+func BenchmarkSafeConcurrentAdd64(b *testing.B) {
+	benchmarkConcurrentAdd(b, NewSafeSet(), 64)
+}
+
+func BenchmarkShardedConcurrentAdd64(b *testing.B) {
+	benchmarkConcurrentAdd(b, NewShardedSet(0), 64)
+}
+
+// benchmarkConcurrentMixed adds an element every 4 operations and checks
+// membership in the others.
+func benchmarkConcurrentMixed(b *testing.B, s Set, goroutines int) {
+	fill(s, 1000)
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < b.N; i += goroutines {
+				if i%4 == 0 {
+					s.Add(i)
+				} else {
+					s.Contains(i)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func BenchmarkSafeConcurrentMixed1(b *testing.B) {
+	benchmarkConcurrentMixed(b, NewSafeSet(), 1)
+}
+
+func BenchmarkShardedConcurrentMixed1(b *testing.B) {
+	benchmarkConcurrentMixed(b, NewShardedSet(0), 1)
+}
+
+func BenchmarkSafeConcurrentMixed8(b *testing.B) {
+	benchmarkConcurrentMixed(b, NewSafeSet(), 8)
+}
+
+func BenchmarkShardedConcurrentMixed8(b *testing.B) {
+	benchmarkConcurrentMixed(b, NewShardedSet(0), 8)
+}
+
+func BenchmarkSafeConcurrentMixed64(b *testing.B) {
+	benchmarkConcurrentMixed(b, NewSafeSet(), 64)
+}
+
+func BenchmarkShardedConcurrentMixed64(b *testing.B) {
+	benchmarkConcurrentMixed(b, NewShardedSet(0), 64)
+}
+
+func benchmarkConcurrentLen(b *testing.B, s Set, goroutines int) {
+	fill(s, 1000)
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < b.N; i += goroutines {
+				s.Len()
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func BenchmarkSafeConcurrentLen8(b *testing.B) {
+	benchmarkConcurrentLen(b, NewSafeSet(), 8)
+}
+
+func BenchmarkShardedConcurrentLen8(b *testing.B) {
+	benchmarkConcurrentLen(b, NewShardedSet(0), 8)
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func Test_shardedSet_Add_Remove(t *testing.T) {
+	s := NewShardedSet(8)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Add(i, strconv.Itoa(i), float64(i))
+			s.Remove(float64(i))
+		}(i)
+	}
+	wg.Wait()
+
+	if s.Len() != 200 {
+		t.Errorf("shardedSet.Len() = %v, want 200", s.Len())
+	}
+	for i := 0; i < 100; i++ {
+		if !s.ContainsAll(i, strconv.Itoa(i)) || s.ContainsAny(float64(i)) {
+			t.Errorf("shardedSet contains wrong elements of %v", i)
+		}
+	}
+	if err := s.Add([]int{1}); err == nil {
+		t.Errorf("shardedSet.Add() unhashable element returns no error")
+	}
+}
+
+func Test_shardedSet_Equality(t *testing.T) {
+	p := &point{1, 2}
+	s := NewShardedSet(8, p, 0.0)
+	p.X = 3
+	if !s.Contains(p) {
+		t.Errorf("Contains() of a mutated pointer = false")
+	}
+	s.Add(p, math.Copysign(0, -1)) //nolint:errcheck
+	if s.Len() != 2 {
+		t.Errorf("Len() = %v, want 2", s.Len())
+	}
+}
+
+func Test_shardedSet_Unhashable(t *testing.T) {
+	u1, u2 := keyedUser{7, []string{"a"}}, keyedUser{8, nil}
+	keyed := NewKeyedSet(nil, 1, u1, u2)
+	s := NewShardedSet(4, 1, 2)
+	if got := s.Unite(keyed); got.Len() != 4 || !got.ContainsAll(1, 2, u1, u2) {
+		t.Errorf("shardedSet.Unite() = %v, want 4 elements", got)
+	}
+	if got := s.SymmetricDiff(keyed); got.Len() != 3 || !got.ContainsAll(2, u1, u2) {
+		t.Errorf("shardedSet.SymmetricDiff() = %v, want 3 elements", got)
+	}
+}
+
+func Test_shardedSet_Range(t *testing.T) {
+	s := NewShardedSet(4, 1, 2, 3, 4, 5, 6, 7, 8)
+	var indexes []int
+	s.Range(func(index int, elem interface{}) bool {
+		indexes = append(indexes, index)
+		return index < 4
+	})
+	if want := []int{0, 1, 2, 3, 4}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("shardedSet.Range() visited %v, want %v", indexes, want)
+	}
+	if got := s.ToInts(); !reflect.DeepEqual(got, []int{1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Errorf("shardedSet.ToInts() = %v", got)
+	}
+	if got := len(s.Elements()); got != 8 {
+		t.Errorf("shardedSet.Elements() has %v elements, want 8", got)
+	}
+}
+
+func Test_shardedSet_Compare(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        Set
+		b        Set
+		equal    bool
+		subset   bool
+		superset bool
+	}{
+		{"", NewShardedSet(4, 1, 2, "3"), NewShardedSet(4, 1, 2, "3"), true, true, true},
+		{"", NewShardedSet(4, 1, 2), NewShardedSet(7, 1, 2, "3"), false, true, false},
+		{"", NewShardedSet(4, 1, 2, "3"), NewSafeSet(1, 2), false, false, true},
+		{"", NewSafeSet(1, 2), NewShardedSet(0, 1, 2), true, true, true},
+		{"", NewSet(1, 3), NewShardedSet(0, 1, 2), false, false, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.equal {
+				t.Errorf("Equal() = %v, want %v", got, tt.equal)
+			}
+			if got := tt.a.IsSubsetOf(tt.b); got != tt.subset {
+				t.Errorf("IsSubsetOf() = %v, want %v", got, tt.subset)
+			}
+			if got := tt.a.IsSupersetOf(tt.b); got != tt.superset {
+				t.Errorf("IsSupersetOf() = %v, want %v", got, tt.superset)
+			}
+		})
+	}
+}
+
+func Test_shardedSet_Operations(t *testing.T) {
+	tests := []struct {
+		name      string
+		a         Set
+		b         Set
+		diff      Set
+		symmetric Set
+		union     Set
+		intersect Set
+	}{
+		{
+			"sharded",
+			NewShardedSet(4, 1, 2, "3", 4.0),
+			NewShardedSet(3, 2, "3", 5),
+			NewSet(1, 4.0),
+			NewSet(1, 4.0, 5),
+			NewSet(1, 2, "3", 4.0, 5),
+			NewSet(2, "3"),
+		},
+		{
+			"safe",
+			NewShardedSet(4, 1, 2, "3"),
+			NewSafeSet(1, 6),
+			NewSet(2, "3"),
+			NewSet(2, "3", 6),
+			NewSet(1, 2, "3", 6),
+			NewSet(1),
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Diff(tt.b); !got.Equal(tt.diff) {
+				t.Errorf("shardedSet.Diff() = %v, want %v", got, tt.diff)
+			}
+			if got := tt.a.SymmetricDiff(tt.b); !got.Equal(tt.symmetric) {
+				t.Errorf("shardedSet.SymmetricDiff() = %v, want %v", got, tt.symmetric)
+			}
+			if got := tt.a.Unite(tt.b); !got.Equal(tt.union) {
+				t.Errorf("shardedSet.Unite() = %v, want %v", got, tt.union)
+			}
+			if got := tt.a.Intersect(tt.b); !got.Equal(tt.intersect) {
+				t.Errorf("shardedSet.Intersect() = %v, want %v", got, tt.intersect)
+			}
+			if got := tt.b.Intersect(tt.a); !got.Equal(tt.intersect) {
+				t.Errorf("Intersect() with sharded set = %v, want %v", got, tt.intersect)
+			}
+		})
+	}
+}
+
+func Test_shardedSet_Concurrent(t *testing.T) {
+	s := NewShardedSet(16)
+	other := NewSafeSet(1, 2, 3)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				s.Add(g*1000 + i)
+			}
+		}(g)
+	}
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				snapshot := s.Copy()
+				if snapshot.Len() != len(snapshot.Elements()) {
+					t.Errorf("shardedSet.Copy() is not consistent")
+				}
+				s.Unite(other)
+				other.Intersect(s)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s.Len() != 2000 {
+		t.Errorf("shardedSet.Len() = %v, want 2000", s.Len())
+	}
+}
+
+func Test_shardedSet_Copy_And_JSON(t *testing.T) {
+	s := NewShardedSet(4, 1, 2, 3)
+	c := s.Copy()
+	c.Add(4)
+	if s.Contains(4) {
+		t.Errorf("shardedSet.Copy() shares storage with the origin set")
+	}
+	if s.ToThreadSafe() != s {
+		t.Errorf("shardedSet.ToThreadSafe() returns a different set")
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != "[1,2,3]" {
+		t.Errorf("json.Marshal() = %s, want [1,2,3]", data)
+	}
+	got := NewShardedSet(2, 5)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !got.Equal(s) {
+		t.Errorf("json.Unmarshal() = %v, want %v", got, s)
+	}
+}
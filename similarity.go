@@ -0,0 +1,121 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import "math"
+
+// cardinalities returns |a|, |b| and |a ∩ b| in a single pass over the
+// smaller set, holding the read locks of both sets if they are thread safe.
+func cardinalities(a, b Set) (la, lb, inter int) {
+	defer rlockSets(a, b)()
+
+	lenA, containsA, foreachA := unsafeView(a)
+	lenB, containsB, foreachB := unsafeView(b)
+	la, lb = lenA(), lenB()
+	foreach, contains := foreachA, containsB
+	if lb < la {
+		foreach, contains = foreachB, containsA
+	}
+	foreach(func(_ int, elem interface{}) bool {
+		if contains(elem) {
+			inter++
+		}
+		return true
+	})
+	return la, lb, inter
+}
+
+// IntersectLen returns the cardinality of the intersection of the two sets
+// without creating the intersection.
+// math formula: |a ∩ b|
+func IntersectLen(a, b Set) int {
+	_, _, inter := cardinalities(a, b)
+	return inter
+}
+
+// UnionLen returns the cardinality of the union of the two sets without
+// creating the union.
+// math formula: |a ∪ b| = |a| + |b| - |a ∩ b|
+func UnionLen(a, b Set) int {
+	la, lb, inter := cardinalities(a, b)
+	return la + lb - inter
+}
+
+// Jaccard returns the Jaccard index of the two sets. It is 1 if both sets
+// are empty.
+// math formula: |a ∩ b| / |a ∪ b|
+func Jaccard(a, b Set) float64 {
+	la, lb, inter := cardinalities(a, b)
+	if la+lb == 0 {
+		return 1
+	}
+	return float64(inter) / float64(la+lb-inter)
+}
+
+// JaccardDistance returns the Jaccard distance of the two sets.
+// math formula: 1 - |a ∩ b| / |a ∪ b|
+func JaccardDistance(a, b Set) float64 {
+	return 1 - Jaccard(a, b)
+}
+
+// Dice returns the Sørensen–Dice coefficient of the two sets. It is 1 if
+// both sets are empty.
+// math formula: 2|a ∩ b| / (|a| + |b|)
+func Dice(a, b Set) float64 {
+	la, lb, inter := cardinalities(a, b)
+	if la+lb == 0 {
+		return 1
+	}
+	return 2 * float64(inter) / float64(la+lb)
+}
+
+// DiceDistance returns the Sørensen–Dice distance of the two sets.
+// math formula: 1 - 2|a ∩ b| / (|a| + |b|)
+func DiceDistance(a, b Set) float64 {
+	return 1 - Dice(a, b)
+}
+
+// Overlap returns the overlap coefficient of the two sets, which is 1 if
+// one set is the subset of the other, including an empty set.
+// math formula: |a ∩ b| / min(|a|, |b|)
+func Overlap(a, b Set) float64 {
+	la, lb, inter := cardinalities(a, b)
+	if la == 0 || lb == 0 {
+		return 1
+	}
+	return float64(inter) / float64(minInt(la, lb))
+}
+
+// Cosine returns the cosine similarity of the two sets as binary vectors.
+// It is 1 if both sets are empty, and 0 if only one of them is empty.
+// math formula: |a ∩ b| / sqrt(|a| * |b|)
+func Cosine(a, b Set) float64 {
+	la, lb, inter := cardinalities(a, b)
+	if la+lb == 0 {
+		return 1
+	}
+	if la == 0 || lb == 0 {
+		return 0
+	}
+	return float64(inter) / math.Sqrt(float64(la)*float64(lb))
+}
+
+// CosineDistance returns the cosine distance of the two sets.
+// math formula: 1 - |a ∩ b| / sqrt(|a| * |b|)
+func CosineDistance(a, b Set) float64 {
+	return 1 - Cosine(a, b)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func Test_Similarity(t *testing.T) {
+	type want struct {
+		inter, union                   int
+		jaccard, dice, overlap, cosine float64
+	}
+	tests := []struct {
+		name string
+		a    Set
+		b    Set
+		want want
+	}{
+		{"disjoint", NewSet(1, 2), NewSet("1", "2"), want{0, 4, 0, 0, 0, 0}},
+		{"same", NewSet(1, "a"), NewSafeSet(1, "a"), want{2, 2, 1, 1, 1, 1}},
+		{"partial", NewSet(1, 2, 3, 4), NewSet(3, 4, 5, 6, 7, 8, 9, 10, 11), want{2, 11, 2.0 / 11, 4.0 / 13, 0.5, 2 / 6.0}},
+		{"subset", NewSafeSet(1, 2), NewShardedSet(4, 1, 2, 3, 4), want{2, 4, 0.5, 2.0 / 3, 1, 2 / math.Sqrt(8)}},
+		{"both empty", NewSet(), NewSafeSet(), want{0, 0, 1, 1, 1, 1}},
+		{"one empty", NewSet(), NewSet(1), want{0, 1, 0, 0, 1, 0}},
+		{"bitmap and ordered", NewBitmapSet(1, 2, 3), NewOrderedSet(3, 2, "x"), want{2, 4, 0.5, 2 * 2 / 6.0, 2 / 3.0, 2 / 3.0}},
+	}
+	near := func(x, y float64) bool {
+		return math.Abs(x-y) < 1e-9
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IntersectLen(tt.a, tt.b); got != tt.want.inter {
+				t.Errorf("IntersectLen() = %v, want %v", got, tt.want.inter)
+			}
+			if got := UnionLen(tt.a, tt.b); got != tt.want.union {
+				t.Errorf("UnionLen() = %v, want %v", got, tt.want.union)
+			}
+			if got := Jaccard(tt.a, tt.b); !near(got, tt.want.jaccard) || !near(JaccardDistance(tt.a, tt.b), 1-got) {
+				t.Errorf("Jaccard() = %v, want %v", got, tt.want.jaccard)
+			}
+			if got := Dice(tt.a, tt.b); !near(got, tt.want.dice) || !near(DiceDistance(tt.a, tt.b), 1-got) {
+				t.Errorf("Dice() = %v, want %v", got, tt.want.dice)
+			}
+			if got := Overlap(tt.a, tt.b); !near(got, tt.want.overlap) {
+				t.Errorf("Overlap() = %v, want %v", got, tt.want.overlap)
+			}
+			if got := Cosine(tt.a, tt.b); !near(got, tt.want.cosine) || !near(CosineDistance(tt.a, tt.b), 1-got) {
+				t.Errorf("Cosine() = %v, want %v", got, tt.want.cosine)
+			}
+			// symmetric
+			if IntersectLen(tt.b, tt.a) != tt.want.inter || !near(Jaccard(tt.b, tt.a), tt.want.jaccard) {
+				t.Errorf("similarity is not symmetric")
+			}
+		})
+	}
+}
+
+func Test_Similarity_Concurrent(t *testing.T) {
+	a := NewSafeSet()
+	b := NewShardedSet(4)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(3)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				a.Add(g*1000 + i) //nolint:errcheck
+				b.Add(g*1000 + i) //nolint:errcheck
+			}
+		}(g)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				Jaccard(a, b)
+				a.Equal(b)
+				a.Equal(a)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				IntersectLen(b, a)
+				b.IsSubsetOf(a)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := Jaccard(a, b); got != 1 {
+		t.Errorf("Jaccard() = %v, want 1", got)
+	}
+}
@@ -0,0 +1,754 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"fmt"
+	gostrings "strings"
+)
+
+// Comparator compares a and b, it returns a negative number if a < b,
+// zero if a == b and a positive number if a > b.
+type Comparator func(a, b interface{}) int
+
+// compareIntsAndStrings is the default Comparator of SortedSet, it orders
+// ints before strings, ints in numeric order and strings in lexical order.
+func compareIntsAndStrings(a, b interface{}) int {
+	ta, tb := typedAssert(a), typedAssert(b)
+	if ta != tb {
+		return int(ta) - int(tb)
+	}
+	if ta == typedString {
+		return gostrings.Compare(a.(string), b.(string))
+	}
+	x, y := a.(int), b.(int)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func validateIntOrString(elem interface{}) error {
+	if typedAssert(elem) == typedAny {
+		return fmt.Errorf("error add %v of type %T to sorted set, only int and string are supported without comparator", elem, elem)
+	}
+	return nil
+}
+
+// treapNode is a node of treap, which is a binary search tree by elements
+// and a heap by priorities. Each node records the size of its subtree to
+// support rank and select.
+type treapNode struct {
+	elem        interface{}
+	priority    uint64
+	size        int
+	left, right *treapNode
+}
+
+func (n *treapNode) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *treapNode) update() {
+	n.size = n.left.len() + n.right.len() + 1
+}
+
+func (n *treapNode) copy() *treapNode {
+	if n == nil {
+		return nil
+	}
+	return &treapNode{
+		elem:     n.elem,
+		priority: n.priority,
+		size:     n.size,
+		left:     n.left.copy(),
+		right:    n.right.copy(),
+	}
+}
+
+// walk visits elements in ascending order, it stops when foreach returns
+// false and reports whether the walk is completed.
+func (n *treapNode) walk(foreach func(elem interface{}) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.walk(foreach) && foreach(n.elem) && n.right.walk(foreach)
+}
+
+type sortedSet struct {
+	root    *treapNode
+	compare Comparator
+	// validate checks whether an element can be added, it is nil
+	// if the comparator is given by user.
+	validate func(elem interface{}) error
+	seed     uint64
+}
+
+func newSortedSet(compare Comparator, elems ...interface{}) *sortedSet {
+	s := &sortedSet{
+		compare: compare,
+		seed:    0x9e3779b97f4a7c15,
+	}
+	if compare == nil {
+		s.compare = compareIntsAndStrings
+		s.validate = validateIntOrString
+	}
+	err := s.Add(elems...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// empty returns a new empty sorted set with the same comparator.
+func (s *sortedSet) empty() *sortedSet {
+	return &sortedSet{
+		compare:  s.compare,
+		validate: s.validate,
+		seed:     s.seed,
+	}
+}
+
+// nextPriority returns a pseudo random priority by xorshift.
+func (s *sortedSet) nextPriority() uint64 {
+	s.seed ^= s.seed << 13
+	s.seed ^= s.seed >> 7
+	s.seed ^= s.seed << 17
+	return s.seed
+}
+
+func (s *sortedSet) comparable(elem interface{}) bool {
+	return s.validate == nil || s.validate(elem) == nil
+}
+
+// split splits the tree n into two trees, the elements in the left one are
+// less than elem, and the others are in the right one.
+func (s *sortedSet) split(n *treapNode, elem interface{}) (*treapNode, *treapNode) {
+	if n == nil {
+		return nil, nil
+	}
+	if s.compare(n.elem, elem) < 0 {
+		l, r := s.split(n.right, elem)
+		n.right = l
+		n.update()
+		return n, r
+	}
+	l, r := s.split(n.left, elem)
+	n.left = r
+	n.update()
+	return l, n
+}
+
+// merge merges two trees, all elements in l must be less than the ones in r.
+func merge(l, r *treapNode) *treapNode {
+	if l == nil {
+		return r
+	}
+	if r == nil {
+		return l
+	}
+	if l.priority > r.priority {
+		l.right = merge(l.right, r)
+		l.update()
+		return l
+	}
+	r.left = merge(l, r.left)
+	r.update()
+	return r
+}
+
+func (s *sortedSet) insert(n, node *treapNode) *treapNode {
+	if n == nil {
+		return node
+	}
+	if node.priority > n.priority {
+		node.left, node.right = s.split(n, node.elem)
+		node.update()
+		return node
+	}
+	if s.compare(node.elem, n.elem) < 0 {
+		n.left = s.insert(n.left, node)
+	} else {
+		n.right = s.insert(n.right, node)
+	}
+	n.update()
+	return n
+}
+
+func (s *sortedSet) delete(n *treapNode, elem interface{}) *treapNode {
+	if n == nil {
+		return nil
+	}
+	c := s.compare(elem, n.elem)
+	switch {
+	case c < 0:
+		n.left = s.delete(n.left, elem)
+	case c > 0:
+		n.right = s.delete(n.right, elem)
+	default:
+		return merge(n.left, n.right)
+	}
+	n.update()
+	return n
+}
+
+func (s *sortedSet) find(elem interface{}) *treapNode {
+	n := s.root
+	for n != nil {
+		c := s.compare(elem, n.elem)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// add adds the elem which has been validated.
+func (s *sortedSet) add(elem interface{}) {
+	if s.find(elem) != nil {
+		return
+	}
+	s.root = s.insert(s.root, &treapNode{
+		elem:     elem,
+		priority: s.nextPriority(),
+		size:     1,
+	})
+}
+
+func (s *sortedSet) Add(elems ...interface{}) error {
+	if s.validate != nil {
+		for _, elem := range elems {
+			if err := s.validate(elem); err != nil {
+				return err
+			}
+		}
+	}
+	for _, elem := range elems {
+		s.add(elem)
+	}
+	return nil
+}
+
+func (s *sortedSet) Extend(b interface{}) error {
+	return extend(s, b)
+}
+
+func (s *sortedSet) Remove(elems ...interface{}) {
+	for _, elem := range elems {
+		if s.Contains(elem) {
+			s.root = s.delete(s.root, elem)
+		}
+	}
+}
+
+func (s *sortedSet) Contains(elem interface{}) bool {
+	return s.comparable(elem) && s.find(elem) != nil
+}
+
+// ContainsErr returns an *UnhashableError if the given elem is unhashable
+// and the set uses the default comparator. The other elements which can not
+// be ordered by the default comparator are never in the set.
+func (s *sortedSet) ContainsErr(elem interface{}) (bool, error) {
+	if s.validate != nil {
+		if !hashable(elem) {
+			return false, unhashableError(elem, 0)
+		}
+		if s.validate(elem) != nil {
+			return false, nil
+		}
+	}
+	return s.find(elem) != nil, nil
+}
+
+func (s *sortedSet) ContainsAll(elems ...interface{}) bool {
+	for _, elem := range elems {
+		if !s.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *sortedSet) ContainsAny(elems ...interface{}) bool {
+	for _, elem := range elems {
+		if s.Contains(elem) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *sortedSet) Copy() Set {
+	c := s.empty()
+	c.root = s.root.copy()
+	return c
+}
+
+func (s *sortedSet) Len() int {
+	return s.root.len()
+}
+
+func (s *sortedSet) String() string {
+	return formatSet(s)
+}
+
+func (s *sortedSet) Range(foreach func(index int, elem interface{}) bool) {
+	i := 0
+	s.root.walk(func(elem interface{}) bool {
+		if !foreach(i, elem) {
+			return false
+		}
+		i++
+		return true
+	})
+}
+
+func (s *sortedSet) Elements() []interface{} {
+	ret := make([]interface{}, 0, s.Len())
+	s.root.walk(func(elem interface{}) bool {
+		ret = append(ret, elem)
+		return true
+	})
+	return ret
+}
+
+func (s *sortedSet) ToStrings() []string {
+	ret := []string{}
+	s.root.walk(func(elem interface{}) bool {
+		if str, ok := elem.(string); ok {
+			ret = append(ret, str)
+		}
+		return true
+	})
+	return ret
+}
+
+func (s *sortedSet) ToInts() []int {
+	ret := []int{}
+	s.root.walk(func(elem interface{}) bool {
+		if i, ok := elem.(int); ok {
+			ret = append(ret, i)
+		}
+		return true
+	})
+	return ret
+}
+
+func (s *sortedSet) ToThreadUnsafe() Set {
+	return s
+}
+
+func (s *sortedSet) ToThreadSafe() Set {
+	return &threadSafeSortedSet{threadSafeSet{unsafe: s}}
+}
+
+func (s *sortedSet) Equal(b Set) bool {
+	b = b.ToThreadUnsafe()
+	if s.Len() != b.Len() {
+		return false
+	}
+	return s.isSubsetOf(b)
+}
+
+func (s *sortedSet) IsSubsetOf(b Set) bool {
+	b = b.ToThreadUnsafe()
+	if s.Len() > b.Len() {
+		return false
+	}
+	return s.isSubsetOf(b)
+}
+
+func (s *sortedSet) IsSupersetOf(b Set) bool {
+	b = b.ToThreadUnsafe()
+	if b.Len() > s.Len() {
+		return false
+	}
+	ret := true
+	b.Range(func(_ int, elem interface{}) bool {
+		ret = s.Contains(elem)
+		return ret
+	})
+	return ret
+}
+
+func (s *sortedSet) isSubsetOf(b Set) bool {
+	return s.root.walk(b.Contains)
+}
+
+// filter returns a new sorted set containing the elements in s for
+// which keep returns true.
+func (s *sortedSet) filter(keep func(elem interface{}) bool) *sortedSet {
+	ret := s.empty()
+	s.root.walk(func(elem interface{}) bool {
+		if keep(elem) {
+			ret.add(elem)
+		}
+		return true
+	})
+	return ret
+}
+
+func (s *sortedSet) Diff(b Set) Set {
+	b = b.ToThreadUnsafe()
+	return s.filter(func(elem interface{}) bool {
+		return !b.Contains(elem)
+	})
+}
+
+// SymmetricDiff returns a new sorted set, unless b holds elements which
+// can not be ordered by the default comparator, then the result is an
+// unordered set holding them.
+func (s *sortedSet) SymmetricDiff(b Set) Set {
+	b = b.ToThreadUnsafe()
+	if s.validateSet(b) != nil {
+		return newSet(s.Elements()...).SymmetricDiff(b)
+	}
+	diff := s.filter(func(elem interface{}) bool {
+		return !b.Contains(elem)
+	})
+	b.Range(func(_ int, elem interface{}) bool {
+		if !s.Contains(elem) {
+			diff.add(elem)
+		}
+		return true
+	})
+	return diff
+}
+
+// Unite returns a new sorted set, unless b holds elements which can not be
+// ordered by the default comparator, then the result is an unordered set
+// holding them.
+func (s *sortedSet) Unite(b Set) Set {
+	b = b.ToThreadUnsafe()
+	if s.validateSet(b) != nil {
+		return newSet(s.Elements()...).Unite(b)
+	}
+	union := s.Copy().(*sortedSet)
+	b.Range(func(_ int, elem interface{}) bool {
+		union.add(elem)
+		return true
+	})
+	return union
+}
+
+func (s *sortedSet) Intersect(b Set) Set {
+	b = b.ToThreadUnsafe()
+	return s.filter(b.Contains)
+}
+
+// validateSet returns an error if some elements in b can not be ordered
+// by the comparator of s.
+func (s *sortedSet) validateSet(b Set) error {
+	if s.validate == nil {
+		return nil
+	}
+	var err error
+	b.Range(func(_ int, elem interface{}) bool {
+		err = s.validate(elem)
+		return err == nil
+	})
+	return err
+}
+
+func (s *sortedSet) UniteWith(b Set) error {
+	b = b.ToThreadUnsafe()
+	if b == Set(s) {
+		return nil
+	}
+	if err := s.validateSet(b); err != nil {
+		return err
+	}
+	b.Range(func(_ int, elem interface{}) bool {
+		s.add(elem)
+		return true
+	})
+	return nil
+}
+
+// removeIf removes the elements for which remove returns true.
+func (s *sortedSet) removeIf(remove func(elem interface{}) bool) {
+	removed := []interface{}{}
+	s.root.walk(func(elem interface{}) bool {
+		if remove(elem) {
+			removed = append(removed, elem)
+		}
+		return true
+	})
+	for _, elem := range removed {
+		s.root = s.delete(s.root, elem)
+	}
+}
+
+func (s *sortedSet) IntersectWith(b Set) {
+	b = b.ToThreadUnsafe()
+	s.removeIf(func(elem interface{}) bool {
+		return !b.Contains(elem)
+	})
+}
+
+func (s *sortedSet) DiffWith(b Set) {
+	b = b.ToThreadUnsafe()
+	if b == Set(s) {
+		s.root = nil
+		return
+	}
+	b.Range(func(_ int, elem interface{}) bool {
+		s.Remove(elem)
+		return true
+	})
+}
+
+func (s *sortedSet) SymmetricDiffWith(b Set) error {
+	b = b.ToThreadUnsafe()
+	if b == Set(s) {
+		s.root = nil
+		return nil
+	}
+	if err := s.validateSet(b); err != nil {
+		return err
+	}
+	b.Range(func(_ int, elem interface{}) bool {
+		if s.find(elem) != nil {
+			s.root = s.delete(s.root, elem)
+		} else {
+			s.add(elem)
+		}
+		return true
+	})
+	return nil
+}
+
+func (s *sortedSet) Min() (interface{}, bool) {
+	return s.Select(0)
+}
+
+func (s *sortedSet) Max() (interface{}, bool) {
+	return s.Select(s.Len() - 1)
+}
+
+func (s *sortedSet) Floor(elem interface{}) (interface{}, bool) {
+	if !s.comparable(elem) {
+		return nil, false
+	}
+	var ret *treapNode
+	for n := s.root; n != nil; {
+		c := s.compare(elem, n.elem)
+		if c == 0 {
+			return n.elem, true
+		}
+		if c < 0 {
+			n = n.left
+		} else {
+			ret = n
+			n = n.right
+		}
+	}
+	if ret == nil {
+		return nil, false
+	}
+	return ret.elem, true
+}
+
+func (s *sortedSet) Ceiling(elem interface{}) (interface{}, bool) {
+	if !s.comparable(elem) {
+		return nil, false
+	}
+	var ret *treapNode
+	for n := s.root; n != nil; {
+		c := s.compare(elem, n.elem)
+		if c == 0 {
+			return n.elem, true
+		}
+		if c > 0 {
+			n = n.right
+		} else {
+			ret = n
+			n = n.left
+		}
+	}
+	if ret == nil {
+		return nil, false
+	}
+	return ret.elem, true
+}
+
+func (s *sortedSet) RangeBetween(lo, hi interface{}) []interface{} {
+	ret := []interface{}{}
+	if !s.comparable(lo) || !s.comparable(hi) {
+		return ret
+	}
+	s.rangeBetween(s.root, lo, hi, func(elem interface{}) {
+		ret = append(ret, elem)
+	})
+	return ret
+}
+
+func (s *sortedSet) rangeBetween(n *treapNode, lo, hi interface{}, foreach func(elem interface{})) {
+	if n == nil {
+		return
+	}
+	geLo := s.compare(n.elem, lo) >= 0
+	leHi := s.compare(n.elem, hi) <= 0
+	if geLo {
+		s.rangeBetween(n.left, lo, hi, foreach)
+	}
+	if geLo && leHi {
+		foreach(n.elem)
+	}
+	if leHi {
+		s.rangeBetween(n.right, lo, hi, foreach)
+	}
+}
+
+func (s *sortedSet) Rank(elem interface{}) int {
+	if !s.comparable(elem) {
+		return -1
+	}
+	rank := 0
+	for n := s.root; n != nil; {
+		c := s.compare(elem, n.elem)
+		if c <= 0 {
+			n = n.left
+		} else {
+			rank += n.left.len() + 1
+			n = n.right
+		}
+	}
+	return rank
+}
+
+func (s *sortedSet) Select(i int) (interface{}, bool) {
+	if i < 0 || i >= s.Len() {
+		return nil, false
+	}
+	n := s.root
+	for {
+		l := n.left.len()
+		switch {
+		case i < l:
+			n = n.left
+		case i > l:
+			i -= l + 1
+			n = n.right
+		default:
+			return n.elem, true
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaler. Sets containing only ints or only
+// strings are encoded as plain JSON arrays in ascending order, the others
+// are encoded in the type-tagged form.
+func (s *sortedSet) MarshalJSON() ([]byte, error) {
+	return marshalElements(s.Elements())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces all elements in
+// the set with the decoded ones.
+func (s *sortedSet) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalElements(data)
+	if err != nil {
+		return err
+	}
+	if elems == nil {
+		return nil
+	}
+	if s.compare == nil {
+		*s = *newSortedSet(nil)
+	}
+	ret := s.empty()
+	if err := ret.Add(elems...); err != nil {
+		return err
+	}
+	*s = *ret
+	return nil
+}
+
+type threadSafeSortedSet struct {
+	threadSafeSet
+}
+
+func newThreadSafeSortedSet(compare Comparator, elems ...interface{}) *threadSafeSortedSet {
+	return &threadSafeSortedSet{threadSafeSet{unsafe: newSortedSet(compare, elems...)}}
+}
+
+func (s *threadSafeSortedSet) sorted() *sortedSet {
+	return s.unsafe.(*sortedSet)
+}
+
+func (s *threadSafeSortedSet) Copy() Set {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &threadSafeSortedSet{threadSafeSet{unsafe: s.unsafe.Copy()}}
+}
+
+func (s *threadSafeSortedSet) ToThreadSafe() Set {
+	return s
+}
+
+func (s *threadSafeSortedSet) Min() (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sorted().Min()
+}
+
+func (s *threadSafeSortedSet) Max() (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sorted().Max()
+}
+
+func (s *threadSafeSortedSet) Floor(elem interface{}) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sorted().Floor(elem)
+}
+
+func (s *threadSafeSortedSet) Ceiling(elem interface{}) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sorted().Ceiling(elem)
+}
+
+func (s *threadSafeSortedSet) RangeBetween(lo, hi interface{}) []interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sorted().RangeBetween(lo, hi)
+}
+
+func (s *threadSafeSortedSet) Rank(elem interface{}) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sorted().Rank(elem)
+}
+
+func (s *threadSafeSortedSet) Select(i int) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sorted().Select(i)
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2026 Jim Zhang (jim.zoumo@gmail.com)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goset
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_sortedSet_Add(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       SortedSet
+		elems   []interface{}
+		want    []interface{}
+		wantErr bool
+	}{
+		{"ints", NewSortedSet(), []interface{}{3, 1, 2, 1}, []interface{}{1, 2, 3}, false},
+		{"mixed", NewSortedSet(), []interface{}{"b", 2, "a", -1}, []interface{}{-1, 2, "a", "b"}, false},
+		{"unsupported", NewSortedSet(1), []interface{}{2, 1.5}, []interface{}{1}, true},
+		{
+			"comparator",
+			NewSortedSetWithComparator(func(a, b interface{}) int {
+				x, y := a.(float64), b.(float64)
+				switch {
+				case x < y:
+					return 1
+				case x > y:
+					return -1
+				}
+				return 0
+			}),
+			[]interface{}{1.5, 3.5, 2.5},
+			[]interface{}{3.5, 2.5, 1.5},
+			false,
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.s.Add(tt.elems...); (err != nil) != tt.wantErr {
+				t.Errorf("sortedSet.Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := tt.s.Elements(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortedSet.Elements() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sortedSet_Queries(t *testing.T) {
+	s := NewSortedSet(10, 20, 30, 40, 50)
+	tests := []struct {
+		name    string
+		elem    interface{}
+		floor   interface{}
+		ceiling interface{}
+		rank    int
+	}{
+		{"below", 5, nil, 10, 0},
+		{"exact", 30, 30, 30, 2},
+		{"between", 35, 30, 40, 3},
+		{"above", 55, 50, nil, 5},
+		{"unsupported", 1.5, nil, nil, -1},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := s.Floor(tt.elem); got != tt.floor {
+				t.Errorf("sortedSet.Floor() = %v, want %v", got, tt.floor)
+			}
+			if got, _ := s.Ceiling(tt.elem); got != tt.ceiling {
+				t.Errorf("sortedSet.Ceiling() = %v, want %v", got, tt.ceiling)
+			}
+			if got := s.Rank(tt.elem); got != tt.rank {
+				t.Errorf("sortedSet.Rank() = %v, want %v", got, tt.rank)
+			}
+		})
+	}
+
+	if got, ok := s.Min(); !ok || got != 10 {
+		t.Errorf("sortedSet.Min() = %v, %v, want 10", got, ok)
+	}
+	if got, ok := s.Max(); !ok || got != 50 {
+		t.Errorf("sortedSet.Max() = %v, %v, want 50", got, ok)
+	}
+	if got, ok := s.Select(3); !ok || got != 40 {
+		t.Errorf("sortedSet.Select() = %v, %v, want 40", got, ok)
+	}
+	if _, ok := s.Select(5); ok {
+		t.Errorf("sortedSet.Select() out of range returns true")
+	}
+	if got, want := s.RangeBetween(15, 40), []interface{}{20, 30, 40}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sortedSet.RangeBetween() = %v, want %v", got, want)
+	}
+	if got := s.RangeBetween(41, 49); len(got) != 0 {
+		t.Errorf("sortedSet.RangeBetween() = %v, want []", got)
+	}
+	if _, ok := NewSortedSet().Min(); ok {
+		t.Errorf("sortedSet.Min() of empty set returns true")
+	}
+}
+
+func Test_sortedSet_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := NewSortedSet()
+	want := map[int]bool{}
+	for i := 0; i < 2000; i++ {
+		n := r.Intn(500)
+		if r.Intn(3) == 0 {
+			s.Remove(n)
+			delete(want, n)
+		} else {
+			s.Add(n)
+			want[n] = true
+		}
+	}
+
+	ints := make([]int, 0, len(want))
+	for n := range want {
+		ints = append(ints, n)
+	}
+	sort.Ints(ints)
+	if got := s.ToInts(); !reflect.DeepEqual(got, ints) {
+		t.Fatalf("sortedSet.ToInts() = %v, want %v", got, ints)
+	}
+	for i, n := range ints {
+		if got := s.Rank(n); got != i {
+			t.Errorf("sortedSet.Rank(%v) = %v, want %v", n, got, i)
+		}
+		if got, _ := s.Select(i); got != n {
+			t.Errorf("sortedSet.Select(%v) = %v, want %v", i, got, n)
+		}
+	}
+}
+
+func Test_sortedSet_Operations(t *testing.T) {
+	a := NewSortedSet(5, 3, 1, "a")
+	b := NewSet(1, 2, "a", "b")
+
+	tests := []struct {
+		name string
+		got  Set
+		want []interface{}
+	}{
+		{"diff", a.Diff(b), []interface{}{3, 5}},
+		{"symmetric diff", a.SymmetricDiff(b), []interface{}{2, 3, 5, "b"}},
+		{"unite", a.Unite(b), []interface{}{1, 2, 3, 5, "a", "b"}},
+		{"intersect", a.Intersect(b), []interface{}{1, "a"}},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := tt.got.(SortedSet); !ok {
+				t.Errorf("sortedSet operation returns %T, want SortedSet", tt.got)
+			}
+			if got := tt.got.Elements(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortedSet operation = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !a.Equal(NewSet(1, 3, 5, "a")) || !NewSet(1, 3, 5, "a").Equal(a) {
+		t.Errorf("sortedSet.Equal() = false, want true")
+	}
+	if !a.IsSupersetOf(NewSortedSet(1, "a")) || a.IsSubsetOf(b) {
+		t.Errorf("sortedSet.IsSupersetOf() or IsSubsetOf() returns wrong result")
+	}
+
+	// unsupported elements are kept in an unordered result
+	if got := a.Unite(NewSet(1.5)); !got.Equal(NewSet(1, 3, 5, "a", 1.5)) {
+		t.Errorf("sortedSet.Unite() with unsupported elements = %v", got)
+	}
+	if got := a.SymmetricDiff(NewSet(1, 1.5)); !got.Equal(NewSet(3, 5, "a", 1.5)) {
+		t.Errorf("sortedSet.SymmetricDiff() with unsupported elements = %v", got)
+	}
+	if got := NewSafeSortedSet(1).Unite(NewSet(1.5)); !got.Equal(NewSet(1, 1.5)) {
+		t.Errorf("safe sortedSet.Unite() with unsupported elements = %v", got)
+	}
+}
+
+func Test_sortedSet_JSON(t *testing.T) {
+	s := NewSafeSortedSet(3, 1, 2)
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != "[1,2,3]" {
+		t.Errorf("json.Marshal() = %s, want [1,2,3]", data)
+	}
+
+	got := NewSortedSet()
+	if err := json.Unmarshal([]byte("[3,2,1]"), got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Elements(), s.Elements()) {
+		t.Errorf("json.Unmarshal() = %v, want %v", got, s)
+	}
+}
+
+func Test_threadSafeSortedSet(t *testing.T) {
+	s := NewSafeSortedSet()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Add(i)
+			s.Floor(i)
+			s.Rank(i)
+		}(i)
+	}
+	wg.Wait()
+
+	if got, _ := s.Select(42); got != 42 {
+		t.Errorf("threadSafeSortedSet.Select() = %v, want 42", got)
+	}
+	if _, ok := s.Copy().(*threadSafeSortedSet); !ok {
+		t.Errorf("threadSafeSortedSet.Copy() = %T, want *threadSafeSortedSet", s.Copy())
+	}
+	if s.ToThreadSafe() != s {
+		t.Errorf("threadSafeSortedSet.ToThreadSafe() returns a different set")
+	}
+}
@@ -16,10 +16,7 @@ limitations under the License.
 
 package goset
 
-import (
-	"fmt"
-	"sync/atomic"
-)
+import "sync/atomic"
 
 // typedSet is a set with specified type
 type typedSet interface {
@@ -79,6 +76,22 @@ type typedSet interface {
 	// Intersect returns the intersection of two set, aka Intersection Set
 	// math formula: a ∩ b
 	Intersect(b typedSet) typedSet
+
+	// ---------------------------------------------------------------------
+	// In-place Set Oprations
+
+	// UniteWith adds all elements in b to this set.
+	UniteWith(b typedSet)
+
+	// IntersectWith removes the elements not in b from this set.
+	IntersectWith(b typedSet)
+
+	// DiffWith removes the elements in b from this set.
+	DiffWith(b typedSet)
+
+	// SymmetricDiffWith removes the elements in b from this set, and adds
+	// the elements only in b.
+	SymmetricDiffWith(b typedSet)
 }
 
 type typed int
@@ -152,14 +165,12 @@ func (s typedSetGroup) typedSetFor(elem interface{}) typedSet {
 	return v.(typedSet)
 }
 
-func (s typedSetGroup) Add(elems ...interface{}) (err error) {
-	defer func() {
-		// recover unhashable error
-		if e := recover(); e != nil {
-			err = fmt.Errorf("%v", e)
-		}
-	}()
-
+// Add adds all elements to the set. It checks all elements before adding,
+// so that nothing is added if any of them is unhashable.
+func (s typedSetGroup) Add(elems ...interface{}) error {
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
 	for _, elem := range elems {
 		s.typedSetFor(elem).Add(elem)
 	}
@@ -168,7 +179,9 @@ func (s typedSetGroup) Add(elems ...interface{}) (err error) {
 
 func (s typedSetGroup) Remove(elems ...interface{}) {
 	for _, elem := range elems {
-		s.typedSetFor(elem).Remove(elem)
+		if hashable(elem) {
+			s.typedSetFor(elem).Remove(elem)
+		}
 	}
 }
 
@@ -183,16 +196,16 @@ func (s typedSetGroup) Range(foreach func(index int, elem interface{}) bool) {
 	})
 }
 
-func (s typedSetGroup) Contains(elem interface{}) (ret bool) {
-	defer func() {
-		// recover unhashable error
-		if e := recover(); e != nil {
-			ret = false
-			return
-		}
-	}()
+func (s typedSetGroup) Contains(elem interface{}) bool {
+	ok, _ := s.ContainsErr(elem)
+	return ok
+}
 
-	return s.typedSetFor(elem).Contains(elem)
+func (s typedSetGroup) ContainsErr(elem interface{}) (bool, error) {
+	if !hashable(elem) {
+		return false, unhashableError(elem, 0)
+	}
+	return s.typedSetFor(elem).Contains(elem), nil
 }
 
 func (s typedSetGroup) ContainsAll(elems ...interface{}) bool {
@@ -281,6 +294,30 @@ func (s typedSetGroup) Intersect(b typedSetGroup) typedSetGroup {
 	return ret
 }
 
+func (s typedSetGroup) UniteWith(b typedSetGroup) {
+	visitAll(func(t typed) {
+		s.load(t).UniteWith(b.load(t))
+	})
+}
+
+func (s typedSetGroup) IntersectWith(b typedSetGroup) {
+	visitAll(func(t typed) {
+		s.load(t).IntersectWith(b.load(t))
+	})
+}
+
+func (s typedSetGroup) DiffWith(b typedSetGroup) {
+	visitAll(func(t typed) {
+		s.load(t).DiffWith(b.load(t))
+	})
+}
+
+func (s typedSetGroup) SymmetricDiffWith(b typedSetGroup) {
+	visitAll(func(t typed) {
+		s.load(t).SymmetricDiffWith(b.load(t))
+	})
+}
+
 func (s typedSetGroup) Elements() []interface{} {
 	ret := make([]interface{}, 0, s.Len())
 
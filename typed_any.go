@@ -127,6 +127,48 @@ func (s any) Intersect(b typedSet) typedSet {
 	return intersection
 }
 
+func (s any) UniteWith(b typedSet) {
+	for key := range b.(any) {
+		s[key] = Empty{}
+	}
+}
+
+func (s any) IntersectWith(b typedSet) {
+	s2 := b.(any)
+	for key := range s {
+		if _, ok := s2[key]; !ok {
+			delete(s, key)
+		}
+	}
+}
+
+func (s any) DiffWith(b typedSet) {
+	s2 := b.(any)
+	// iterate the smaller one
+	if len(s2) < len(s) {
+		for key := range s2 {
+			delete(s, key)
+		}
+		return
+	}
+	for key := range s {
+		if _, ok := s2[key]; ok {
+			delete(s, key)
+		}
+	}
+}
+
+func (s any) SymmetricDiffWith(b typedSet) {
+	// if b is s itself, all keys are deleted and none is added
+	for key := range b.(any) {
+		if _, ok := s[key]; ok {
+			delete(s, key)
+		} else {
+			s[key] = Empty{}
+		}
+	}
+}
+
 func (s any) Range(foreach func(i int, elem interface{}) bool) {
 	i := 0
 	for key := range s {
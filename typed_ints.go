@@ -129,6 +129,48 @@ func (s ints) Intersect(b typedSet) typedSet {
 	return intersection
 }
 
+func (s ints) UniteWith(b typedSet) {
+	for key := range b.(ints) {
+		s[key] = Empty{}
+	}
+}
+
+func (s ints) IntersectWith(b typedSet) {
+	s2 := b.(ints)
+	for key := range s {
+		if _, ok := s2[key]; !ok {
+			delete(s, key)
+		}
+	}
+}
+
+func (s ints) DiffWith(b typedSet) {
+	s2 := b.(ints)
+	// iterate the smaller one
+	if len(s2) < len(s) {
+		for key := range s2 {
+			delete(s, key)
+		}
+		return
+	}
+	for key := range s {
+		if _, ok := s2[key]; ok {
+			delete(s, key)
+		}
+	}
+}
+
+func (s ints) SymmetricDiffWith(b typedSet) {
+	// if b is s itself, all keys are deleted and none is added
+	for key := range b.(ints) {
+		if _, ok := s[key]; ok {
+			delete(s, key)
+		} else {
+			s[key] = Empty{}
+		}
+	}
+}
+
 func (s ints) Range(foreach func(i int, elem interface{}) bool) {
 	i := 0
 	for key := range s {
@@ -129,6 +129,48 @@ func (s strings) Intersect(b typedSet) typedSet {
 	return intersection
 }
 
+func (s strings) UniteWith(b typedSet) {
+	for key := range b.(strings) {
+		s[key] = Empty{}
+	}
+}
+
+func (s strings) IntersectWith(b typedSet) {
+	s2 := b.(strings)
+	for key := range s {
+		if _, ok := s2[key]; !ok {
+			delete(s, key)
+		}
+	}
+}
+
+func (s strings) DiffWith(b typedSet) {
+	s2 := b.(strings)
+	// iterate the smaller one
+	if len(s2) < len(s) {
+		for key := range s2 {
+			delete(s, key)
+		}
+		return
+	}
+	for key := range s {
+		if _, ok := s2[key]; ok {
+			delete(s, key)
+		}
+	}
+}
+
+func (s strings) SymmetricDiffWith(b typedSet) {
+	// if b is s itself, all keys are deleted and none is added
+	for key := range b.(strings) {
+		if _, ok := s[key]; ok {
+			delete(s, key)
+		} else {
+			s[key] = Empty{}
+		}
+	}
+}
+
 func (s strings) Range(foreach func(i int, elem interface{}) bool) {
 	i := 0
 	for key := range s {